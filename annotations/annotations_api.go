@@ -30,9 +30,9 @@ const (
 	// different from bad request and not found
 	UPPServiceUnavailableMsg = "Service unavailable"
 
-	pacAnnotationLifecycle       = "pac"
-	v1AnnotationLifecycle        = "v1"
-	nextVideoAnnotationLifecycle = "next-video"
+	pacAnnotationLifecycle       = mapper.LifecyclePAC
+	v1AnnotationLifecycle        = mapper.LifecycleV1
+	nextVideoAnnotationLifecycle = mapper.LifecycleNextVideo
 )
 
 // UPPError encapsulates error information for errors originating from calls to UPP annotations endpoint.
@@ -87,7 +87,25 @@ func (api *UPPAnnotationsAPI) GetAllButV2(ctx context.Context, contentUUID strin
 	return api.getAnnotations(ctx, contentUUID, pacAnnotationLifecycle, v1AnnotationLifecycle, nextVideoAnnotationLifecycle)
 }
 
+// GetImplicit retrieves the list of implicit annotations UPP derives from the published annotations for given contentUUID,
+// such as implicitlyAbout and implicitlyClassifiedBy. The returned annotations have the derived source.
+func (api *UPPAnnotationsAPI) GetImplicit(ctx context.Context, contentUUID string) ([]Annotation, error) {
+	implicitAnnotations, err := api.convertAnnotations(ctx, contentUUID, mapper.ConvertImplicitPredicates)
+	if err != nil {
+		return nil, err
+	}
+	for i := range implicitAnnotations {
+		implicitAnnotations[i].Source = SourceDerived
+	}
+	return implicitAnnotations, nil
+}
+
 func (api *UPPAnnotationsAPI) getAnnotations(ctx context.Context, contentUUID string, lifecycles ...string) ([]Annotation, error) {
+	return api.convertAnnotations(ctx, contentUUID, mapper.ConvertPredicates, lifecycles...)
+}
+
+// convertAnnotations retrieves the annotations for given contentUUID from UPP and maps them with the given converter.
+func (api *UPPAnnotationsAPI) convertAnnotations(ctx context.Context, contentUUID string, convert func([]byte) ([]byte, error), lifecycles ...string) ([]Annotation, error) {
 	uppResponse, err := api.getUPPAnnotationsResponse(ctx, contentUUID, lifecycles...)
 	if err != nil {
 		return nil, err
@@ -110,7 +128,7 @@ func (api *UPPAnnotationsAPI) getAnnotations(ctx context.Context, contentUUID st
 		return nil, UPPError{msg: UPPServiceUnavailableMsg, status: http.StatusServiceUnavailable, uppBody: nil}
 	}
 
-	convertedBody, err := mapper.ConvertPredicates(respBody)
+	convertedBody, err := convert(respBody)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to map predicates from UPP response")
 	}
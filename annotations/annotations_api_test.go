@@ -265,6 +265,65 @@ func TestGetAnnotationsHappy(t *testing.T) {
 	}
 }
 
+func TestGetImplicitAnnotations(t *testing.T) {
+	uuid := uuid.New().String()
+	tid := "tid_all-good"
+	ctx := tidUtils.TransactionAwareContext(context.TODO(), tid)
+
+	annotationsServerMock := newAnnotationsAPIServerMock(t, tid, uuid, "", http.StatusOK, `[{
+			"predicate": "http://www.ft.com/ontology/annotation/about",
+			"id": "http://api.ft.com/things/29e67a92-a3b8-410c-9139-15abe9b47e12",
+			"apiUrl": "http://api.ft.com/things/29e67a92-a3b8-410c-9139-15abe9b47e12",
+			"types": [
+				"http://www.ft.com/ontology/core/Thing",
+				"http://www.ft.com/ontology/concept/Concept",
+				"http://www.ft.com/ontology/Topic"
+			],
+			"prefLabel": "Global Economy"
+		},
+		{
+			"predicate": "http://www.ft.com/ontology/implicitlyAbout",
+			"id": "http://api.ft.com/things/82645c31-4426-4ef5-99c9-9df6e0940c00",
+			"apiUrl": "http://api.ft.com/things/82645c31-4426-4ef5-99c9-9df6e0940c00",
+			"types": [
+				"http://www.ft.com/ontology/core/Thing",
+				"http://www.ft.com/ontology/concept/Concept",
+				"http://www.ft.com/ontology/Topic"
+			],
+			"prefLabel": "World"
+		}]`)
+	defer annotationsServerMock.Close()
+
+	annotationsAPI := NewUPPAnnotationsAPI(testClient, annotationsServerMock.URL+"/content/%v/annotations", testBasicAuthUsername, testBasicAuthPassword)
+	implicitAnnotations, err := annotationsAPI.GetImplicit(ctx, uuid)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Annotation{
+		{
+			Predicate: "http://www.ft.com/ontology/implicitlyAbout",
+			ConceptId: "http://www.ft.com/thing/82645c31-4426-4ef5-99c9-9df6e0940c00",
+			ApiUrl:    "http://api.ft.com/things/82645c31-4426-4ef5-99c9-9df6e0940c00",
+			Type:      "http://www.ft.com/ontology/Topic",
+			PrefLabel: "World",
+			Source:    SourceDerived,
+		},
+	}, implicitAnnotations)
+}
+
+func TestGetImplicitAnnotationsNotFound(t *testing.T) {
+	uuid := uuid.New().String()
+	tid := "tid_all-good"
+	ctx := tidUtils.TransactionAwareContext(context.TODO(), tid)
+
+	annotationsServerMock := newAnnotationsAPIServerMock(t, tid, uuid, "", http.StatusNotFound, "[]")
+	defer annotationsServerMock.Close()
+
+	annotationsAPI := NewUPPAnnotationsAPI(testClient, annotationsServerMock.URL+"/content/%v/annotations", testBasicAuthUsername, testBasicAuthPassword)
+	_, err := annotationsAPI.GetImplicit(ctx, uuid)
+
+	assert.EqualValues(t, UPPError{msg: UPPNotFoundMsg, status: http.StatusNotFound, uppBody: []byte("[]")}, err)
+}
+
 func newAnnotationsAPIServerMock(t *testing.T, tid string, uuid string, lifecycles string, status int, body string) *httptest.Server {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "/content/"+uuid+annotationsEndpoint, r.URL.Path)
@@ -20,6 +20,7 @@ const PreviousDocumentHashHeader = "Previous-Document-Hash"
 type RW interface {
 	Read(ctx context.Context, contentUUID string) (*Annotations, string, bool, error)
 	Write(ctx context.Context, contentUUID string, annotations *Annotations, hash string) (string, error)
+	Delete(ctx context.Context, contentUUID string, hash string) error
 	Endpoint() string
 	GTG() error
 }
@@ -35,8 +36,71 @@ func NewRW(client *http.Client, endpoint string) RW {
 
 var ErrUnexpectedStatusRead = errors.New("annotations RW returned an unexpected HTTP status code in read operation")
 var ErrUnexpectedStatusWrite = errors.New("annotations RW returned an unexpected HTTP status code in write operation")
+var ErrUnexpectedStatusDelete = errors.New("annotations RW returned an unexpected HTTP status code in delete operation")
 var ErrGTGNotOK = errors.New("gtg returned a non-200 HTTP status")
 
+// Errors returned by the annotations RW write operations, wrapped in an RWError.
+var (
+	ErrConflict           = errors.New("draft annotations have been changed since the previous document hash")
+	ErrPreconditionFailed = errors.New("previous document hash does not match the current draft annotations")
+	ErrDraftNotFound      = errors.New("draft annotations not found")
+	ErrInvalidDraft       = errors.New("draft annotations rejected as invalid by annotations RW")
+	ErrRWUnavailable      = errors.New("annotations RW is unavailable")
+)
+
+// RWError is returned when the annotations RW rejects a write operation.
+// It wraps one of the annotations RW errors above, or the unexpected status error of the operation,
+// and holds the hash of the current draft if the annotations RW returned it.
+type RWError struct {
+	status      int
+	currentHash string
+	err         error
+}
+
+// NewRWError returns an RWError for the given status returned by the annotations RW, wrapping the given error.
+func NewRWError(status int, currentHash string, err error) RWError {
+	return RWError{status: status, currentHash: currentHash, err: err}
+}
+
+// newRWErrorFromResponse returns the RWError for the given annotations RW response,
+// wrapping the given unexpected status error if the status is not one of the expected ones.
+func newRWErrorFromResponse(resp *http.Response, unexpected error) RWError {
+	var err error
+	switch resp.StatusCode {
+	case http.StatusConflict:
+		err = ErrConflict
+	case http.StatusPreconditionFailed:
+		err = ErrPreconditionFailed
+	case http.StatusNotFound:
+		err = ErrDraftNotFound
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		err = ErrInvalidDraft
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		err = ErrRWUnavailable
+	default:
+		err = unexpected
+	}
+	return NewRWError(resp.StatusCode, resp.Header.Get(DocumentHashHeader), err)
+}
+
+func (e RWError) Error() string {
+	return fmt.Sprintf("status %d: %v", e.status, e.err)
+}
+
+func (e RWError) Unwrap() error {
+	return e.err
+}
+
+// Status returns the HTTP status returned by the annotations RW.
+func (e RWError) Status() int {
+	return e.status
+}
+
+// CurrentHash returns the hash of the current draft, if the annotations RW returned it.
+func (e RWError) CurrentHash() string {
+	return e.currentHash
+}
+
 func (rw *annotationsRW) Read(ctx context.Context, contentUUID string) (*Annotations, string, bool, error) {
 	tid, err := tidUtils.GetTransactionIDFromContext(ctx)
 
@@ -121,7 +185,45 @@ func (rw *annotationsRW) Write(ctx context.Context, contentUUID string, annotati
 		newHash := resp.Header.Get(DocumentHashHeader)
 		return newHash, nil
 	default:
-		return "", fmt.Errorf("status %d: %w", resp.StatusCode, ErrUnexpectedStatusWrite)
+		return "", newRWErrorFromResponse(resp, ErrUnexpectedStatusWrite)
+	}
+}
+
+// Delete removes the draft annotations of the given content, so that the published annotations are served again.
+func (rw *annotationsRW) Delete(ctx context.Context, contentUUID string, hash string) error {
+	tid, err := tidUtils.GetTransactionIDFromContext(ctx)
+
+	if err != nil {
+		tid = tidUtils.NewTransactionID()
+		log.WithField(tidUtils.TransactionIDKey, tid).
+			WithField("uuid", contentUUID).
+			WithError(err).
+			Warn("Transaction ID error in deleting annotations from RW: Generated a new transaction ID")
+		ctx = tidUtils.TransactionAwareContext(ctx, tid)
+	}
+
+	deleteLog := log.WithField(tidUtils.TransactionIDKey, tid).WithField("uuid", contentUUID)
+
+	req, err := http.NewRequest("DELETE", fmt.Sprintf(rwURLPattern, rw.endpoint, contentUUID), nil)
+	if err != nil {
+		deleteLog.WithError(err).Error("Error in creating the HTTP delete request to annotations RW")
+		return err
+	}
+
+	req.Header.Set(PreviousDocumentHashHeader, hash)
+
+	resp, err := rw.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		deleteLog.WithError(err).Error("Error making the HTTP delete request to annotations RW")
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		return nil
+	default:
+		return newRWErrorFromResponse(resp, ErrUnexpectedStatusDelete)
 	}
 }
 
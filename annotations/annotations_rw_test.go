@@ -186,6 +186,39 @@ func TestUnhappyWriteStatus500(t *testing.T) {
 	assert.True(t, errors.Is(err, ErrUnexpectedStatusWrite))
 }
 
+func TestWriteRWErrors(t *testing.T) {
+	tests := map[int]error{
+		http.StatusConflict:            ErrConflict,
+		http.StatusPreconditionFailed:  ErrPreconditionFailed,
+		http.StatusNotFound:            ErrDraftNotFound,
+		http.StatusBadRequest:          ErrInvalidDraft,
+		http.StatusUnprocessableEntity: ErrInvalidDraft,
+		http.StatusServiceUnavailable:  ErrRWUnavailable,
+		http.StatusGatewayTimeout:      ErrRWUnavailable,
+		http.StatusTeapot:              ErrUnexpectedStatusWrite,
+	}
+
+	for status, expectedErr := range tests {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			tid := tidUtils.NewTransactionID()
+			oldHash := randomdata.RandStringRunes(56)
+			currentHash := randomdata.RandStringRunes(56)
+			s := newAnnotationsRWServerMock(t, http.MethodPut, status, testRWBody, oldHash, currentHash, tid)
+			defer s.Close()
+
+			rw := NewRW(testClient, s.URL)
+			ctx := tidUtils.TransactionAwareContext(context.Background(), tid)
+			_, err := rw.Write(ctx, testContentUUID, &expectedCanonicalizedAnnotations, oldHash)
+			assert.True(t, errors.Is(err, expectedErr))
+
+			var rwErr RWError
+			assert.True(t, errors.As(err, &rwErr))
+			assert.Equal(t, status, rwErr.Status())
+			assert.Equal(t, currentHash, rwErr.CurrentHash())
+		})
+	}
+}
+
 func TestWriteHTTPRequestError(t *testing.T) {
 	tid := tidUtils.NewTransactionID()
 	oldHash := randomdata.RandStringRunes(56)
@@ -228,6 +261,53 @@ func TestWriteMissingTID(t *testing.T) {
 	}
 }
 
+func TestHappyDelete(t *testing.T) {
+	tid := tidUtils.NewTransactionID()
+	oldHash := randomdata.RandStringRunes(56)
+	s := newAnnotationsRWServerMock(t, http.MethodDelete, http.StatusNoContent, "", oldHash, "", tid)
+	defer s.Close()
+
+	rw := NewRW(testClient, s.URL)
+	ctx := tidUtils.TransactionAwareContext(context.Background(), tid)
+	err := rw.Delete(ctx, testContentUUID, oldHash)
+	assert.NoError(t, err)
+}
+
+func TestDeleteDraftNotFound(t *testing.T) {
+	tid := tidUtils.NewTransactionID()
+	oldHash := randomdata.RandStringRunes(56)
+	s := newAnnotationsRWServerMock(t, http.MethodDelete, http.StatusNotFound, "", oldHash, "", tid)
+	defer s.Close()
+
+	rw := NewRW(testClient, s.URL)
+	ctx := tidUtils.TransactionAwareContext(context.Background(), tid)
+	err := rw.Delete(ctx, testContentUUID, oldHash)
+	assert.True(t, errors.Is(err, ErrDraftNotFound))
+}
+
+func TestUnhappyDeleteStatus500(t *testing.T) {
+	tid := tidUtils.NewTransactionID()
+	oldHash := randomdata.RandStringRunes(56)
+	s := newAnnotationsRWServerMock(t, http.MethodDelete, http.StatusInternalServerError, "", oldHash, "", tid)
+	defer s.Close()
+
+	rw := NewRW(testClient, s.URL)
+	ctx := tidUtils.TransactionAwareContext(context.Background(), tid)
+	err := rw.Delete(ctx, testContentUUID, oldHash)
+	assert.True(t, errors.Is(err, ErrUnexpectedStatusDelete))
+}
+
+func TestDeleteHTTPCallError(t *testing.T) {
+	tid := tidUtils.NewTransactionID()
+	rw := NewRW(testClient, "")
+	ctx := tidUtils.TransactionAwareContext(context.Background(), tid)
+	err := rw.Delete(ctx, testContentUUID, "")
+
+	var urlError *url.Error
+	assert.True(t, errors.As(err, &urlError))
+	assert.Equal(t, urlError.Op, "Delete")
+}
+
 func TestRWTimeout(t *testing.T) {
 	r := vestigo.NewRouter()
 	r.Put("/drafts/content/:uuid/annotations", func(w http.ResponseWriter, r *http.Request) {
@@ -266,6 +346,8 @@ func newAnnotationsRWServerMock(t *testing.T, method string, status int, body st
 			assert.Equal(t, hashIn, r.Header.Get(PreviousDocumentHashHeader))
 			rBody, _ := io.ReadAll(r.Body)
 			assert.JSONEq(t, body, string(rBody))
+		case http.MethodDelete:
+			assert.Equal(t, hashIn, r.Header.Get(PreviousDocumentHashHeader))
 		}
 	}))
 	return ts
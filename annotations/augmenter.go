@@ -2,6 +2,7 @@ package annotations
 
 import (
 	"context"
+	"sort"
 	"strings"
 
 	"github.com/Financial-Times/draft-annotations-api/concept"
@@ -18,9 +19,82 @@ func NewAugmenter(api concept.ReadAPI) *Augmenter {
 	return &Augmenter{api}
 }
 
-func (a *Augmenter) AugmentAnnotations(ctx context.Context, canonicalAnnotations []Annotation) ([]Annotation, error) {
-	tid, err := tidUtils.GetTransactionIDFromContext(ctx)
+// UnresolvedConcept is a concept of the annotations whose data has not been found, with the predicates it is annotated with.
+type UnresolvedConcept struct {
+	ConceptId  string   `json:"id"`
+	Predicates []string `json:"predicates"`
+}
 
+// AugmentAnnotations augments the annotations with the concept data. The annotations whose concept data
+// has not been found are removed from the returned list and reported as unresolved concepts.
+func (a *Augmenter) AugmentAnnotations(ctx context.Context, canonicalAnnotations []Annotation) ([]Annotation, []UnresolvedConcept, error) {
+	augmented, unresolved, err := a.AugmentAnnotationsBatch(ctx, map[string][]Annotation{singleList: canonicalAnnotations})
+	if err != nil {
+		return nil, nil, err
+	}
+	return augmented[singleList], unresolved[singleList], nil
+}
+
+// AugmentAnnotationsOrDegrade augments the annotations like AugmentAnnotations, but does not fail when the concept data
+// cannot be read. In that case the annotations are augmented with the concept data which could be read or is cached,
+// the others are kept unaugmented instead of being reported as unresolved, and the returned flag reports
+// the degraded augmentation.
+func (a *Augmenter) AugmentAnnotationsOrDegrade(ctx context.Context, canonicalAnnotations []Annotation) ([]Annotation, []UnresolvedConcept, bool) {
+	augmented, unresolved, degraded := a.AugmentAnnotationsBatchOrDegrade(ctx, map[string][]Annotation{singleList: canonicalAnnotations})
+	return augmented[singleList], unresolved[singleList], degraded
+}
+
+// AugmentAnnotationsBatch augments several lists of annotations at once, fetching the concept data
+// for all of them with a single call to the concepts API. The returned map has the same keys as the given one.
+// As in AugmentAnnotations, the annotations whose concept data has not been found are removed from the returned lists
+// and reported as unresolved concepts, under the key of their list.
+func (a *Augmenter) AugmentAnnotationsBatch(ctx context.Context, batch map[string][]Annotation) (map[string][]Annotation, map[string][]UnresolvedConcept, error) {
+	ctx, tid, dedupedBatch, uuids := prepareAugmentation(ctx, batch)
+
+	concepts, err := a.conceptRead.GetConceptsByIDs(ctx, uuids)
+	if err != nil {
+		log.WithField(tidUtils.TransactionIDKey, tid).
+			WithError(err).Error("Request failed when attempting to augment annotations from UPP concept data")
+		return nil, nil, err
+	}
+
+	augmentedBatch, unresolvedBatch := augmentBatch(tid, dedupedBatch, concepts)
+	log.WithField(tidUtils.TransactionIDKey, tid).Info("Annotations augmented with concept data")
+	return augmentedBatch, unresolvedBatch, nil
+}
+
+// AugmentAnnotationsBatchOrDegrade augments several lists of annotations like AugmentAnnotationsBatch, but does not fail
+// when the concept data cannot be read. In that case all the lists are augmented partially as in AugmentAnnotationsOrDegrade,
+// and the returned flag reports the degraded augmentation.
+func (a *Augmenter) AugmentAnnotationsBatchOrDegrade(ctx context.Context, batch map[string][]Annotation) (map[string][]Annotation, map[string][]UnresolvedConcept, bool) {
+	ctx, tid, dedupedBatch, uuids := prepareAugmentation(ctx, batch)
+
+	concepts, err := a.conceptRead.GetConceptsByIDs(ctx, uuids)
+	if err == nil {
+		augmentedBatch, unresolvedBatch := augmentBatch(tid, dedupedBatch, concepts)
+		log.WithField(tidUtils.TransactionIDKey, tid).Info("Annotations augmented with concept data")
+		return augmentedBatch, unresolvedBatch, false
+	}
+
+	log.WithField(tidUtils.TransactionIDKey, tid).
+		WithError(err).Warn("Request failed when attempting to augment annotations from UPP concept data, augmenting them partially")
+
+	available := a.availableConcepts(uuids, concepts)
+	augmentedBatch := make(map[string][]Annotation, len(dedupedBatch))
+	for key, dedupedCanonical := range dedupedBatch {
+		augmentedBatch[key] = augmentPartially(dedupedCanonical, available)
+	}
+	return augmentedBatch, nil, true
+}
+
+// singleList is the key of the list of annotations augmented on its own as a batch.
+const singleList = ""
+
+// prepareAugmentation returns a context holding the transaction ID of the given one, or a new transaction ID if it has none,
+// that transaction ID, the given lists of annotations deduplicated and without the invalid predicates under the same keys,
+// and the UUIDs of all their concepts.
+func prepareAugmentation(ctx context.Context, batch map[string][]Annotation) (context.Context, string, map[string][]Annotation, []string) {
+	tid, err := tidUtils.GetTransactionIDFromContext(ctx)
 	if err != nil {
 		tid = tidUtils.NewTransactionID()
 		log.WithField(tidUtils.TransactionIDKey, tid).
@@ -29,50 +103,145 @@ func (a *Augmenter) AugmentAnnotations(ctx context.Context, canonicalAnnotations
 		ctx = tidUtils.TransactionAwareContext(ctx, tid)
 	}
 
-	dedupedCanonical := dedupeCanonicalAnnotations(canonicalAnnotations)
-	dedupedCanonical = filterOutInvalidPredicates(dedupedCanonical)
+	dedupedBatch := make(map[string][]Annotation, len(batch))
+	var allAnnotations []Annotation
+	for key, canonicalAnnotations := range batch {
+		dedupedCanonical := filterOutInvalidPredicates(dedupeCanonicalAnnotations(canonicalAnnotations))
+		dedupedBatch[key] = dedupedCanonical
+		allAnnotations = append(allAnnotations, dedupedCanonical...)
+	}
+	return ctx, tid, dedupedBatch, getConceptUUIDs(allAnnotations)
+}
 
-	uuids := getConceptUUIDs(dedupedCanonical)
+// augmentBatch returns each of the given lists augmented with the given concepts, and the concepts of each list
+// which have not been found, under the key of the list if there are any.
+func augmentBatch(tid string, dedupedBatch map[string][]Annotation, concepts map[string]concept.Concept) (map[string][]Annotation, map[string][]UnresolvedConcept) {
+	augmentedBatch := make(map[string][]Annotation, len(dedupedBatch))
+	unresolvedBatch := make(map[string][]UnresolvedConcept)
+	for key, dedupedCanonical := range dedupedBatch {
+		augmented, unresolved := augment(tid, dedupedCanonical, concepts)
+		augmentedBatch[key] = augmented
+		if len(unresolved) > 0 {
+			unresolvedBatch[key] = unresolved
+		}
+	}
+	return augmentedBatch, unresolvedBatch
+}
 
-	concepts, err := a.conceptRead.GetConceptsByIDs(ctx, uuids)
+// availableConcepts returns the concepts which have been read despite the error, as partial results are returned
+// along with it, completed with the cached ones when the concepts reader has a cache.
+func (a *Augmenter) availableConcepts(uuids []string, concepts map[string]concept.Concept) map[string]concept.Concept {
+	available := make(map[string]concept.Concept, len(concepts))
+	for uuid, c := range concepts {
+		available[uuid] = c
+	}
+	if cache, ok := a.conceptRead.(concept.CachedReader); ok {
+		for uuid, c := range cache.GetCachedConceptsByIDs(uuids) {
+			if _, found := available[uuid]; !found {
+				available[uuid] = c
+			}
+		}
+	}
+	return available
+}
 
-	if err != nil {
-		log.WithField(tidUtils.TransactionIDKey, tid).
-			WithError(err).Error("Request failed when attempting to augment annotations from UPP concept data")
-		return nil, err
+// augmentPartially returns the annotations augmented with the available concepts, keeping the others unaugmented.
+func augmentPartially(dedupedCanonical []Annotation, available map[string]concept.Concept) []Annotation {
+	augmentedAnnotations := make([]Annotation, 0, len(dedupedCanonical))
+	for _, ann := range dedupedCanonical {
+		if c, found := available[extractUUID(ann.ConceptId)]; found {
+			ann = augmentAnnotation(ann, c)
+		}
+		augmentedAnnotations = append(augmentedAnnotations, ann)
 	}
+	return augmentedAnnotations
+}
 
+// augment returns the annotations augmented with the given concepts, and the concepts which have not been found.
+func augment(tid string, dedupedCanonical []Annotation, concepts map[string]concept.Concept) ([]Annotation, []UnresolvedConcept) {
 	augmentedAnnotations := make([]Annotation, 0)
+	unresolved := make(map[string][]string)
 	for _, ann := range dedupedCanonical {
 		uuid := extractUUID(ann.ConceptId)
 		concept, found := concepts[uuid]
 		if found {
-			ann.ConceptId = concept.ID
-			ann.ApiUrl = concept.ApiUrl
-			ann.PrefLabel = concept.PrefLabel
-			ann.IsFTAuthor = concept.IsFTAuthor
-			ann.Type = concept.Type
-			augmentedAnnotations = append(augmentedAnnotations, ann)
+			augmentedAnnotations = append(augmentedAnnotations, augmentAnnotation(ann, concept))
 		} else {
 			log.WithField(tidUtils.TransactionIDKey, tid).
 				WithField("conceptId", ann.ConceptId).
-				Warn("Concept data for this annotation was not found, and will be removed from the list of annotations.")
+				Warn("Concept data for this annotation was not found, it is reported as unresolved.")
+			unresolved[ann.ConceptId] = append(unresolved[ann.ConceptId], ann.Predicate)
 		}
 	}
+	return augmentedAnnotations, unresolvedConcepts(unresolved)
+}
 
-	log.WithField(tidUtils.TransactionIDKey, tid).Info("Annotations augmented with concept data")
-	return augmentedAnnotations, nil
+func unresolvedConcepts(predicatesByConcept map[string][]string) []UnresolvedConcept {
+	if len(predicatesByConcept) == 0 {
+		return nil
+	}
+	unresolved := make([]UnresolvedConcept, 0, len(predicatesByConcept))
+	for conceptID, predicates := range predicatesByConcept {
+		sort.Strings(predicates)
+		unresolved = append(unresolved, UnresolvedConcept{ConceptId: conceptID, Predicates: predicates})
+	}
+	sort.Slice(unresolved, func(i, j int) bool {
+		return unresolved[i].ConceptId < unresolved[j].ConceptId
+	})
+	return unresolved
+}
+
+// UnresolvedAnnotations returns the annotations of the given unresolved concepts, as they have been augmented:
+// the given annotations are deduplicated and filtered for invalid predicates like AugmentAnnotations does,
+// and only the ones with an unresolved concept and one of its reported predicates are kept.
+func UnresolvedAnnotations(list []Annotation, unresolved []UnresolvedConcept) []Annotation {
+	unresolvedPredicates := make(map[annotationKey]struct{})
+	for _, u := range unresolved {
+		for _, predicate := range u.Predicates {
+			unresolvedPredicates[annotationKey{predicate: predicate, conceptID: u.ConceptId}] = struct{}{}
+		}
+	}
+
+	var result []Annotation
+	for _, ann := range filterOutInvalidPredicates(dedupeCanonicalAnnotations(list)) {
+		if _, found := unresolvedPredicates[keyOf(ann)]; found {
+			result = append(result, ann)
+		}
+	}
+	return result
+}
+
+func augmentAnnotation(ann Annotation, concept concept.Concept) Annotation {
+	ann.ConceptId = concept.ID
+	ann.ApiUrl = concept.ApiUrl
+	ann.PrefLabel = concept.PrefLabel
+	ann.IsFTAuthor = concept.IsFTAuthor
+	ann.Type = concept.Type
+	return ann
 }
 
+// annotationKey identifies an annotation by its predicate and concept ID.
+type annotationKey struct {
+	predicate string
+	conceptID string
+}
+
+func keyOf(ann Annotation) annotationKey {
+	return annotationKey{predicate: ann.Predicate, conceptID: ann.ConceptId}
+}
+
+// dedupeCanonicalAnnotations keeps the first of the annotations with the same predicate and concept ID,
+// whatever their other fields such as lifecycle, provenance or scores.
 func dedupeCanonicalAnnotations(annotations []Annotation) []Annotation {
-	var empty struct{}
 	var deduped []Annotation
-	dedupedMap := make(map[Annotation]struct{})
+	seen := make(map[annotationKey]struct{})
 	for _, ann := range annotations {
-		dedupedMap[ann] = empty
-	}
-	for k := range dedupedMap {
-		deduped = append(deduped, k)
+		key := keyOf(ann)
+		if _, found := seen[key]; found {
+			continue
+		}
+		seen[key] = struct{}{}
+		deduped = append(deduped, ann)
 	}
 	return deduped
 }
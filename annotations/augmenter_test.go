@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/Financial-Times/draft-annotations-api/concept"
+	"github.com/Financial-Times/draft-annotations-api/mapper"
 	tidUtils "github.com/Financial-Times/transactionid-utils-go"
 	log "github.com/sirupsen/logrus"
 	logTest "github.com/sirupsen/logrus/hooks/test"
@@ -122,11 +123,21 @@ func TestAugmentAnnotations(t *testing.T) {
 		Return(testConcepts, nil)
 	a := NewAugmenter(conceptRead)
 
-	annotations, err := a.AugmentAnnotations(ctx, testCanonicalizedAnnotations)
+	annotations, unresolved, err := a.AugmentAnnotations(ctx, testCanonicalizedAnnotations)
 
 	assert.NoError(t, err)
 	assert.Equal(t, len(expectedAugmentedAnnotations), len(annotations))
 	assert.ElementsMatch(t, annotations, expectedAugmentedAnnotations)
+	assert.Equal(t, []UnresolvedConcept{
+		{
+			ConceptId:  "http://www.ft.com/thing/1a2a1a0a-7199-38b8-8a73-e651e2172471",
+			Predicates: []string{"http://www.ft.com/ontology/annotation/mentions"},
+		},
+		{
+			ConceptId:  "http://www.ft.com/thing/1fb3faf1-bf00-3a15-8efb-1038a59653f7",
+			Predicates: []string{"http://www.ft.com/ontology/annotation/mentions"},
+		},
+	}, unresolved)
 	conceptRead.AssertExpectations(t)
 }
 
@@ -154,7 +165,7 @@ func TestAugmentAnnotationsFixtures(t *testing.T) {
 			a := NewAugmenter(conceptRead)
 
 			originalAnnotations := helperGetAnnotationsFromFixture(t, "augmenter-input-"+test.fixtureBaseName)
-			annotations, err := a.AugmentAnnotations(ctx, originalAnnotations)
+			annotations, _, err := a.AugmentAnnotations(ctx, originalAnnotations)
 			assert.NoError(t, err)
 
 			expectedAnnotations := helperGetAnnotationsFromFixture(t, "augmenter-expected-"+test.fixtureBaseName)
@@ -182,7 +193,7 @@ func TestAugmentAnnotationsArrayShouldNotBeNull(t *testing.T) {
 		Return(make(map[string]concept.Concept), nil)
 	a := NewAugmenter(conceptRead)
 
-	annotations, err := a.AugmentAnnotations(ctx, testCanonicalizedAnnotations)
+	annotations, _, err := a.AugmentAnnotations(ctx, testCanonicalizedAnnotations)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, annotations)
@@ -230,7 +241,7 @@ func TestAugmentAnnotationsConceptSearchError(t *testing.T) {
 		Return(map[string]concept.Concept{}, errors.New("one minute to midnight"))
 	a := NewAugmenter(conceptRead)
 
-	_, err := a.AugmentAnnotations(ctx, testCanonicalizedAnnotations)
+	_, _, err := a.AugmentAnnotations(ctx, testCanonicalizedAnnotations)
 
 	assert.Error(t, err)
 
@@ -249,7 +260,7 @@ func TestAugmentAnnotationsWithInvalidConceptID(t *testing.T) {
 	a := NewAugmenter(conceptRead)
 
 	testCanonicalizedAnnotations = append(testCanonicalizedAnnotations, Annotation{ConceptId: "xyz"})
-	annotations, err := a.AugmentAnnotations(ctx, testCanonicalizedAnnotations)
+	annotations, _, err := a.AugmentAnnotations(ctx, testCanonicalizedAnnotations)
 
 	assert.NoError(t, err)
 	assert.Equal(t, len(expectedAugmentedAnnotations), len(annotations))
@@ -260,6 +271,201 @@ func TestAugmentAnnotationsWithInvalidConceptID(t *testing.T) {
 	conceptRead.AssertExpectations(t)
 }
 
+func TestAugmentAnnotationsBatch(t *testing.T) {
+	matcher := mock.MatchedBy(func(l1 []string) bool {
+		return assert.ElementsMatch(t, l1, testConceptIDs)
+	})
+
+	conceptRead := new(ConceptReadAPIMock)
+	ctx := tidUtils.TransactionAwareContext(context.Background(), tidUtils.NewTransactionID())
+	conceptRead.
+		On("GetConceptsByIDs", ctx, matcher).
+		Return(testConcepts, nil).
+		Once()
+	a := NewAugmenter(conceptRead)
+
+	batch := map[string][]Annotation{
+		"first":  testCanonicalizedAnnotations[:2],
+		"second": testCanonicalizedAnnotations[2:],
+		"empty":  {},
+	}
+	augmented, unresolved, err := a.AugmentAnnotationsBatch(ctx, batch)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]UnresolvedConcept{
+		"first":  {{ConceptId: "http://www.ft.com/thing/1a2a1a0a-7199-38b8-8a73-e651e2172471", Predicates: []string{"http://www.ft.com/ontology/annotation/mentions"}}},
+		"second": {{ConceptId: "http://www.ft.com/thing/1fb3faf1-bf00-3a15-8efb-1038a59653f7", Predicates: []string{"http://www.ft.com/ontology/annotation/mentions"}}},
+	}, unresolved)
+	assert.Len(t, augmented, 3)
+	assert.ElementsMatch(t, augmented["first"], expectedAugmentedAnnotations[:1])
+	assert.ElementsMatch(t, augmented["second"], expectedAugmentedAnnotations[1:])
+	assert.NotNil(t, augmented["empty"])
+	assert.Len(t, augmented["empty"], 0)
+	conceptRead.AssertExpectations(t)
+}
+
+func TestAugmentAnnotationsBatchConceptSearchError(t *testing.T) {
+	conceptRead := new(ConceptReadAPIMock)
+	ctx := tidUtils.TransactionAwareContext(context.Background(), tidUtils.NewTransactionID())
+	conceptRead.
+		On("GetConceptsByIDs", ctx, mock.Anything).
+		Return(map[string]concept.Concept{}, errors.New("one minute to midnight"))
+	a := NewAugmenter(conceptRead)
+
+	_, _, err := a.AugmentAnnotationsBatch(ctx, map[string][]Annotation{"first": testCanonicalizedAnnotations})
+
+	assert.Error(t, err)
+	conceptRead.AssertExpectations(t)
+}
+
+func TestAugmentAnnotationsOrDegrade(t *testing.T) {
+	conceptRead := new(ConceptReadAPIMock)
+	ctx := tidUtils.TransactionAwareContext(context.Background(), tidUtils.NewTransactionID())
+	conceptRead.
+		On("GetConceptsByIDs", ctx, mock.Anything).
+		Return(testConcepts, nil)
+	a := NewAugmenter(conceptRead)
+
+	annotations, unresolved, degraded := a.AugmentAnnotationsOrDegrade(ctx, testCanonicalizedAnnotations[:5])
+
+	assert.False(t, degraded)
+	assert.ElementsMatch(t, expectedAugmentedAnnotations, annotations)
+	assert.Len(t, unresolved, 2)
+	conceptRead.AssertExpectations(t)
+}
+
+type cachedConceptReadAPIMock struct {
+	ConceptReadAPIMock
+	cached map[string]concept.Concept
+}
+
+func (m *cachedConceptReadAPIMock) GetCachedConceptsByIDs(ids []string) map[string]concept.Concept {
+	result := make(map[string]concept.Concept)
+	for _, id := range ids {
+		if c, found := m.cached[id]; found {
+			result[id] = c
+		}
+	}
+	return result
+}
+
+func TestAugmentAnnotationsOrDegradeConceptSearchError(t *testing.T) {
+	subjectUUID := "b224ad07-c818-3ad6-94af-a4d351dbb619"
+	authorUUID := "5bd49568-6d7c-3c10-a5b0-2f3fd5974a6b"
+
+	conceptRead := &cachedConceptReadAPIMock{cached: map[string]concept.Concept{authorUUID: testConcepts[authorUUID]}}
+	ctx := tidUtils.TransactionAwareContext(context.Background(), tidUtils.NewTransactionID())
+	// the concept of the subject has been read before the failure, the author is cached
+	conceptRead.
+		On("GetConceptsByIDs", ctx, mock.Anything).
+		Return(map[string]concept.Concept{subjectUUID: testConcepts[subjectUUID]}, errors.New("one minute to midnight"))
+	a := NewAugmenter(conceptRead)
+
+	annotations, unresolved, degraded := a.AugmentAnnotationsOrDegrade(ctx, testCanonicalizedAnnotations[:5])
+
+	assert.True(t, degraded)
+	assert.Empty(t, unresolved)
+	assert.ElementsMatch(t, []Annotation{
+		expectedAugmentedAnnotations[0],
+		expectedAugmentedAnnotations[1],
+		testCanonicalizedAnnotations[1],
+		testCanonicalizedAnnotations[3],
+		testCanonicalizedAnnotations[4],
+	}, annotations)
+	conceptRead.AssertExpectations(t)
+}
+
+func TestAugmentAnnotationsPartialResults(t *testing.T) {
+	subjectUUID := "b224ad07-c818-3ad6-94af-a4d351dbb619"
+	partialErr := &concept.PartialResultsError{Failed: []concept.BatchError{{IDs: []string{"5bd49568-6d7c-3c10-a5b0-2f3fd5974a6b"}, Err: errors.New("one minute to midnight")}}}
+
+	conceptRead := new(ConceptReadAPIMock)
+	ctx := tidUtils.TransactionAwareContext(context.Background(), tidUtils.NewTransactionID())
+	conceptRead.
+		On("GetConceptsByIDs", ctx, mock.Anything).
+		Return(map[string]concept.Concept{subjectUUID: testConcepts[subjectUUID]}, partialErr)
+	a := NewAugmenter(conceptRead)
+
+	_, _, err := a.AugmentAnnotations(ctx, testCanonicalizedAnnotations[:5])
+	assert.ErrorIs(t, err, partialErr)
+
+	// the degraded augmentation uses the concepts of the batches fetched successfully
+	annotations, unresolved, degraded := a.AugmentAnnotationsOrDegrade(ctx, testCanonicalizedAnnotations[:5])
+	assert.True(t, degraded)
+	assert.Empty(t, unresolved)
+	assert.ElementsMatch(t, []Annotation{
+		expectedAugmentedAnnotations[0],
+		testCanonicalizedAnnotations[1],
+		testCanonicalizedAnnotations[2],
+		testCanonicalizedAnnotations[3],
+		testCanonicalizedAnnotations[4],
+	}, annotations)
+	conceptRead.AssertExpectations(t)
+}
+
+func TestAugmentAnnotationsBatchOrDegradeConceptSearchError(t *testing.T) {
+	subjectUUID := "b224ad07-c818-3ad6-94af-a4d351dbb619"
+	authorUUID := "5bd49568-6d7c-3c10-a5b0-2f3fd5974a6b"
+
+	conceptRead := &cachedConceptReadAPIMock{cached: map[string]concept.Concept{authorUUID: testConcepts[authorUUID]}}
+	ctx := tidUtils.TransactionAwareContext(context.Background(), tidUtils.NewTransactionID())
+	conceptRead.
+		On("GetConceptsByIDs", ctx, mock.Anything).
+		Return(map[string]concept.Concept{subjectUUID: testConcepts[subjectUUID]}, errors.New("one minute to midnight"))
+	a := NewAugmenter(conceptRead)
+
+	augmented, unresolved, degraded := a.AugmentAnnotationsBatchOrDegrade(ctx, map[string][]Annotation{
+		"first":  testCanonicalizedAnnotations[:2],
+		"second": testCanonicalizedAnnotations[2:5],
+	})
+
+	assert.True(t, degraded)
+	assert.Empty(t, unresolved)
+	assert.ElementsMatch(t, []Annotation{expectedAugmentedAnnotations[0], testCanonicalizedAnnotations[1]}, augmented["first"])
+	assert.ElementsMatch(t, []Annotation{
+		expectedAugmentedAnnotations[1],
+		testCanonicalizedAnnotations[3],
+		testCanonicalizedAnnotations[4],
+	}, augmented["second"])
+	conceptRead.AssertExpectations(t)
+}
+
+func TestDedupeCanonicalAnnotationsIgnoresLifecycleAndProvenance(t *testing.T) {
+	list := []Annotation{
+		{Predicate: about, ConceptId: patchConceptA, Lifecycle: "pac", Provenance: "human"},
+		{Predicate: about, ConceptId: patchConceptA, Lifecycle: "v2", Provenance: "machine"},
+		{Predicate: mentions, ConceptId: patchConceptA, Lifecycle: "v2"},
+	}
+
+	assert.Equal(t, []Annotation{list[0], list[2]}, dedupeCanonicalAnnotations(list))
+}
+
+func TestDedupeCanonicalAnnotationsIgnoresScores(t *testing.T) {
+	list := []Annotation{
+		{Predicate: about, ConceptId: patchConceptA, RelevanceScore: score(0.8), ConfidenceScore: score(0.95)},
+		{Predicate: about, ConceptId: patchConceptA, RelevanceScore: score(0.4)},
+	}
+
+	assert.Equal(t, list[:1], dedupeCanonicalAnnotations(list))
+}
+
+func TestUnresolvedAnnotations(t *testing.T) {
+	list := []Annotation{
+		{Predicate: about, ConceptId: patchConceptA},
+		{Predicate: mentions, ConceptId: patchConceptB, Provenance: "human"},
+		{Predicate: mentions, ConceptId: patchConceptB, Provenance: "machine"},
+		{Predicate: mapper.PredicateImplicitlyAbout, ConceptId: patchConceptB},
+		{Predicate: about, ConceptId: patchConceptB},
+	}
+	unresolved := []UnresolvedConcept{
+		{ConceptId: patchConceptB, Predicates: []string{about, mentions}},
+	}
+
+	// the duplicates and the annotations with invalid predicates are not kept
+	assert.ElementsMatch(t, []Annotation{list[1], list[4]}, UnresolvedAnnotations(list, unresolved))
+	assert.Empty(t, UnresolvedAnnotations(list, nil))
+}
+
 type ConceptReadAPIMock struct {
 	mock.Mock
 }
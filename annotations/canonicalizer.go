@@ -31,14 +31,37 @@ func (c *Canonicalizer) Canonicalize(in []Annotation) []Annotation {
 	return out
 }
 
+// deplete keeps the predicate and concept ID of the annotation, which identify it,
+// and its lifecycle, provenance and scores, which are saved with the draft.
 func (c *Canonicalizer) deplete(in Annotation) *Annotation {
-	return &Annotation{Predicate: in.Predicate, ConceptId: in.ConceptId}
+	return &Annotation{
+		Predicate:       in.Predicate,
+		ConceptId:       in.ConceptId,
+		Lifecycle:       in.Lifecycle,
+		Provenance:      in.Provenance,
+		RelevanceScore:  copyScore(in.RelevanceScore),
+		ConfidenceScore: copyScore(in.ConfidenceScore),
+	}
 }
 
-// Hash hashes the given payload in SHA224 + Hex
+// copyScore returns a copy of the given score, so that the canonical annotations do not share it with the original ones.
+func copyScore(score *float64) *float64 {
+	if score == nil {
+		return nil
+	}
+	s := *score
+	return &s
+}
+
+// Hash hashes the given payload in SHA224 + Hex.
+// Only the predicates and concept IDs are hashed, so that a change of lifecycle, provenance or scores
+// does not change the hash.
 func (c *Canonicalizer) hash(ann []Annotation) string {
 	out := bytes.NewBuffer([]byte{})
 	canonical := c.Canonicalize(ann)
+	for i, a := range canonical {
+		canonical[i] = Annotation{Predicate: a.Predicate, ConceptId: a.ConceptId}
+	}
 	json.NewEncoder(out).Encode(canonical)
 	hash := sha256.New224()
 	hash.Write(out.Bytes())
@@ -1,6 +1,7 @@
 package annotations
 
 import (
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
@@ -135,20 +136,20 @@ func TestCanonicalizer(t *testing.T) {
 
 	annotations := []Annotation{
 		{
-			mentions,
-			conceptUuid[0],
-			apiUrl[0],
-			testType,
-			prefLabel[0],
-			false,
+			Predicate:  mentions,
+			ConceptId:  conceptUuid[0],
+			ApiUrl:     apiUrl[0],
+			Type:       testType,
+			PrefLabel:  prefLabel[0],
+			IsFTAuthor: false,
 		},
 		{
-			about,
-			conceptUuid[1],
-			apiUrl[1],
-			testType,
-			prefLabel[1],
-			false,
+			Predicate:  about,
+			ConceptId:  conceptUuid[1],
+			ApiUrl:     apiUrl[1],
+			Type:       testType,
+			PrefLabel:  prefLabel[1],
+			IsFTAuthor: false,
 		},
 	}
 
@@ -199,20 +200,20 @@ func TestCanonicalizerHash(t *testing.T) {
 
 	annotations1 := []Annotation{
 		{
-			mentions,
-			conceptUuid[0],
-			apiUrl[0],
-			testType,
-			prefLabel[0],
-			false,
+			Predicate:  mentions,
+			ConceptId:  conceptUuid[0],
+			ApiUrl:     apiUrl[0],
+			Type:       testType,
+			PrefLabel:  prefLabel[0],
+			IsFTAuthor: false,
 		},
 		{
-			about,
-			conceptUuid[1],
-			apiUrl[1],
-			testType,
-			prefLabel[1],
-			false,
+			Predicate:  about,
+			ConceptId:  conceptUuid[1],
+			ApiUrl:     apiUrl[1],
+			Type:       testType,
+			PrefLabel:  prefLabel[1],
+			IsFTAuthor: false,
 		},
 	}
 
@@ -236,3 +237,121 @@ func TestCanonicalizerHash(t *testing.T) {
 	h2 := c14n.hash(annotations2)
 	assert.Equal(t, h1, h2, "canonical hash values")
 }
+
+func TestCanonicalizerKeepsLifecycleAndProvenance(t *testing.T) {
+	conceptID := uuid.New().String()
+	annotations := []Annotation{
+		{
+			Predicate:  about,
+			ConceptId:  conceptID,
+			PrefLabel:  "Some concept",
+			Lifecycle:  "v2",
+			Provenance: "machine",
+		},
+	}
+
+	c14n := NewCanonicalizer(NewCanonicalAnnotationSorter)
+	actual := c14n.Canonicalize(annotations)
+
+	assert.Equal(t, []Annotation{
+		{
+			Predicate:  about,
+			ConceptId:  conceptID,
+			Lifecycle:  "v2",
+			Provenance: "machine",
+		},
+	}, actual)
+}
+
+func TestCanonicalizerHashIgnoresLifecycleAndProvenance(t *testing.T) {
+	conceptID := uuid.New().String()
+	annotations1 := []Annotation{
+		{
+			Predicate:  about,
+			ConceptId:  conceptID,
+			Lifecycle:  "v2",
+			Provenance: "machine",
+		},
+	}
+	annotations2 := []Annotation{
+		{
+			Predicate:  about,
+			ConceptId:  conceptID,
+			Lifecycle:  "pac",
+			Provenance: "human",
+		},
+	}
+
+	c14n := NewCanonicalizer(NewCanonicalAnnotationSorter)
+	assert.Equal(t, c14n.hash(annotations1), c14n.hash(annotations2), "canonical hash values")
+	assert.Equal(t, "v2", annotations1[0].Lifecycle, "the original annotation structs must not have been altered")
+}
+
+func TestCanonicalizerKeepsScores(t *testing.T) {
+	conceptID := uuid.New().String()
+	annotations := []Annotation{
+		{
+			Predicate:       about,
+			ConceptId:       conceptID,
+			PrefLabel:       "Some concept",
+			RelevanceScore:  score(0.8),
+			ConfidenceScore: score(0.95),
+		},
+	}
+
+	c14n := NewCanonicalizer(NewCanonicalAnnotationSorter)
+	actual := c14n.Canonicalize(annotations)
+
+	assert.Equal(t, []Annotation{
+		{
+			Predicate:       about,
+			ConceptId:       conceptID,
+			RelevanceScore:  score(0.8),
+			ConfidenceScore: score(0.95),
+		},
+	}, actual)
+}
+
+func TestCanonicalizerHashIgnoresScores(t *testing.T) {
+	conceptID := uuid.New().String()
+	annotations1 := []Annotation{
+		{
+			Predicate:       about,
+			ConceptId:       conceptID,
+			RelevanceScore:  score(0.8),
+			ConfidenceScore: score(0.95),
+		},
+	}
+	annotations2 := []Annotation{
+		{
+			Predicate:      about,
+			ConceptId:      conceptID,
+			RelevanceScore: score(0.4),
+		},
+	}
+
+	c14n := NewCanonicalizer(NewCanonicalAnnotationSorter)
+	assert.Equal(t, c14n.hash(annotations1), c14n.hash(annotations2), "canonical hash values")
+}
+
+func TestCanonicalizerKeepsZeroScores(t *testing.T) {
+	conceptID := uuid.New().String()
+	annotations := []Annotation{
+		{Predicate: about, ConceptId: conceptID, RelevanceScore: score(0)},
+	}
+
+	c14n := NewCanonicalizer(NewCanonicalAnnotationSorter)
+	body, err := json.Marshal(c14n.Canonicalize(annotations))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[{"predicate":"`+about+`","id":"`+conceptID+`","relevanceScore":0}]`, string(body))
+
+	var actual []Annotation
+	err = json.Unmarshal(body, &actual)
+	assert.NoError(t, err)
+	assert.Equal(t, score(0), actual[0].RelevanceScore)
+	assert.Nil(t, actual[0].ConfidenceScore)
+}
+
+func score(s float64) *float64 {
+	return &s
+}
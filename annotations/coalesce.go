@@ -0,0 +1,91 @@
+package annotations
+
+import (
+	"context"
+	"time"
+
+	"github.com/Financial-Times/draft-annotations-api/coalesce"
+)
+
+type rwReadResult struct {
+	annotations *Annotations
+	hash        string
+	found       bool
+}
+
+// coalescingRW is a RW sharing one read of the annotations RW between the concurrent reads of the same content.
+type coalescingRW struct {
+	RW
+	reads coalesce.Group[rwReadResult]
+}
+
+// NewCoalescingRW returns a RW coalescing the concurrent reads of the draft annotations of the same content
+// into one read of the given RW, bound by the given timeout. Writes and deletes are not coalesced.
+// A read joining a read in flight may miss a write completed in the meantime,
+// so the current draft must not be read with it before a write.
+func NewCoalescingRW(rw RW, timeout time.Duration) RW {
+	return &coalescingRW{RW: rw, reads: coalesce.Group[rwReadResult]{Timeout: timeout}}
+}
+
+func (rw *coalescingRW) Read(ctx context.Context, contentUUID string) (*Annotations, string, bool, error) {
+	result, _, err := rw.reads.Do(ctx, contentUUID, func(ctx context.Context) (rwReadResult, error) {
+		annotations, hash, found, err := rw.RW.Read(ctx, contentUUID)
+		return rwReadResult{annotations: annotations, hash: hash, found: found}, err
+	})
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	// the callers sharing the read get their own copy of the annotations
+	var annotations *Annotations
+	if result.annotations != nil {
+		annotations = &Annotations{Annotations: copyAnnotations(result.annotations.Annotations)}
+	}
+	return annotations, result.hash, result.found, nil
+}
+
+// CoalescingAnnotationsAPI is a UPPAnnotationsAPI sharing one call to UPP between the concurrent identical reads
+// of the published annotations of the same content.
+type CoalescingAnnotationsAPI struct {
+	*UPPAnnotationsAPI
+	reads coalesce.Group[[]Annotation]
+}
+
+// NewCoalescingAnnotationsAPI returns a CoalescingAnnotationsAPI reading the published annotations with the given API,
+// each shared call being bound by the given timeout.
+func NewCoalescingAnnotationsAPI(api *UPPAnnotationsAPI, timeout time.Duration) *CoalescingAnnotationsAPI {
+	return &CoalescingAnnotationsAPI{UPPAnnotationsAPI: api, reads: coalesce.Group[[]Annotation]{Timeout: timeout}}
+}
+
+// GetAll retrieves the list of published annotations for given contentUUID, see UPPAnnotationsAPI.GetAll.
+func (api *CoalescingAnnotationsAPI) GetAll(ctx context.Context, contentUUID string) ([]Annotation, error) {
+	return api.read(ctx, "all", contentUUID, api.UPPAnnotationsAPI.GetAll)
+}
+
+// GetAllButV2 retrieves the list of published annotations for given contentUUID but filtering v2 annotations,
+// see UPPAnnotationsAPI.GetAllButV2.
+func (api *CoalescingAnnotationsAPI) GetAllButV2(ctx context.Context, contentUUID string) ([]Annotation, error) {
+	return api.read(ctx, "allButV2", contentUUID, api.UPPAnnotationsAPI.GetAllButV2)
+}
+
+// GetImplicit retrieves the list of implicit annotations for given contentUUID, see UPPAnnotationsAPI.GetImplicit.
+func (api *CoalescingAnnotationsAPI) GetImplicit(ctx context.Context, contentUUID string) ([]Annotation, error) {
+	return api.read(ctx, "implicit", contentUUID, api.UPPAnnotationsAPI.GetImplicit)
+}
+
+func (api *CoalescingAnnotationsAPI) read(ctx context.Context, kind string, contentUUID string, get func(context.Context, string) ([]Annotation, error)) ([]Annotation, error) {
+	result, _, err := api.reads.Do(ctx, kind+"/"+contentUUID, func(ctx context.Context) ([]Annotation, error) {
+		return get(ctx, contentUUID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return copyAnnotations(result), nil
+}
+
+func copyAnnotations(annotations []Annotation) []Annotation {
+	if annotations == nil {
+		return nil
+	}
+	return append(make([]Annotation, 0, len(annotations)), annotations...)
+}
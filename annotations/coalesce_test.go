@@ -0,0 +1,105 @@
+package annotations
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tidUtils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/stretchr/testify/assert"
+)
+
+const coalesceContentUUID = "83a201c6-60cd-11e7-91a7-502f7ee26895"
+
+type blockingRW struct {
+	RW
+	reads   int32
+	release chan struct{}
+}
+
+func (rw *blockingRW) Read(_ context.Context, _ string) (*Annotations, string, bool, error) {
+	atomic.AddInt32(&rw.reads, 1)
+	<-rw.release
+	return &Annotations{Annotations: []Annotation{{Predicate: about, ConceptId: patchConceptA}}}, "a-hash", true, nil
+}
+
+// concurrently calls read n times once the first call is in flight, and returns when they are all done
+func callConcurrently(n int, inFlight func() bool, release chan struct{}, read func()) {
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			read()
+		}()
+	}
+	for !inFlight() {
+		time.Sleep(time.Millisecond)
+	}
+	// let the other calls join the one in flight
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+}
+
+func TestCoalescingRWRead(t *testing.T) {
+	rw := &blockingRW{release: make(chan struct{})}
+	coalescing := NewCoalescingRW(rw, time.Second)
+
+	var lock sync.Mutex
+	var results []*Annotations
+	callConcurrently(5, func() bool { return atomic.LoadInt32(&rw.reads) > 0 }, rw.release, func() {
+		annotations, hash, found, err := coalescing.Read(context.Background(), coalesceContentUUID)
+		assert.NoError(t, err)
+		assert.Equal(t, "a-hash", hash)
+		assert.True(t, found)
+
+		lock.Lock()
+		results = append(results, annotations)
+		lock.Unlock()
+	})
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&rw.reads))
+	assert.Len(t, results, 5)
+	// each caller can change its annotations without affecting the others
+	results[0].Annotations[0].Predicate = mentions
+	for _, annotations := range results[1:] {
+		assert.Equal(t, []Annotation{{Predicate: about, ConceptId: patchConceptA}}, annotations.Annotations)
+	}
+}
+
+func TestCoalescingAnnotationsAPIGetAll(t *testing.T) {
+	var requests int32
+	release := make(chan struct{})
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		<-release
+		_, _ = w.Write([]byte(`[{
+			"predicate": "http://www.ft.com/ontology/annotation/about",
+			"id": "http://api.ft.com/things/29e67a92-a3b8-410c-9139-15abe9b47e12",
+			"apiUrl": "http://api.ft.com/things/29e67a92-a3b8-410c-9139-15abe9b47e12",
+			"types": ["http://www.ft.com/ontology/Topic"],
+			"prefLabel": "Global Economy"
+		}]`))
+	}))
+	defer s.Close()
+
+	api := NewCoalescingAnnotationsAPI(NewUPPAnnotationsAPI(testClient, s.URL+"/content/%v/annotations", testBasicAuthUsername, testBasicAuthPassword), time.Second)
+	ctx := tidUtils.TransactionAwareContext(context.Background(), tidUtils.NewTransactionID())
+
+	callConcurrently(5, func() bool { return atomic.LoadInt32(&requests) > 0 }, release, func() {
+		annotations, err := api.GetAll(ctx, coalesceContentUUID)
+		assert.NoError(t, err)
+		assert.Len(t, annotations, 1)
+	})
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	// reads with different lifecycles are not shared
+	_, err := api.GetAllButV2(ctx, coalesceContentUUID)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+}
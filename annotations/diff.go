@@ -0,0 +1,106 @@
+package annotations
+
+import "sort"
+
+// Diff describes the differences between two lists of annotations,
+// compared on their canonical (predicate, concept ID) pairs.
+type Diff struct {
+	Added            []Annotation      `json:"added"`
+	Removed          []Annotation      `json:"removed"`
+	PredicateChanged []PredicateChange `json:"predicateChanged"`
+}
+
+// PredicateChange describes a concept that is annotated in both lists but with different predicates.
+type PredicateChange struct {
+	ConceptId  string   `json:"id"`
+	ApiUrl     string   `json:"apiUrl,omitempty"`
+	Type       string   `json:"type,omitempty"`
+	PrefLabel  string   `json:"prefLabel,omitempty"`
+	IsFTAuthor bool     `json:"isFTAuthor,omitempty"`
+	From       []string `json:"from"`
+	To         []string `json:"to"`
+}
+
+// IsEmpty returns true if the compared lists have the same canonical annotations.
+func (d Diff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.PredicateChanged) == 0
+}
+
+// Compare returns the changes needed to go from one list of annotations to another.
+// Concepts that are only in the "to" list are reported as added, concepts that are only in the "from" list as removed,
+// and concepts that are in both lists with different sets of predicates as predicate changes.
+func Compare(from []Annotation, to []Annotation) Diff {
+	fromByConcept := groupByConcept(from)
+	toByConcept := groupByConcept(to)
+
+	diff := Diff{
+		Added:            make([]Annotation, 0),
+		Removed:          make([]Annotation, 0),
+		PredicateChanged: make([]PredicateChange, 0),
+	}
+
+	for conceptID, toAnnotations := range toByConcept {
+		fromAnnotations, found := fromByConcept[conceptID]
+		if !found {
+			diff.Added = append(diff.Added, toAnnotations...)
+			continue
+		}
+		fromPredicates := predicates(fromAnnotations)
+		toPredicates := predicates(toAnnotations)
+		if !equalStrings(fromPredicates, toPredicates) {
+			diff.PredicateChanged = append(diff.PredicateChanged, PredicateChange{
+				ConceptId: conceptID,
+				From:      fromPredicates,
+				To:        toPredicates,
+			})
+		}
+	}
+	for conceptID, fromAnnotations := range fromByConcept {
+		if _, found := toByConcept[conceptID]; !found {
+			diff.Removed = append(diff.Removed, fromAnnotations...)
+		}
+	}
+
+	sort.Sort(NewCanonicalAnnotationSorter(diff.Added))
+	sort.Sort(NewCanonicalAnnotationSorter(diff.Removed))
+	sort.Slice(diff.PredicateChanged, func(i, j int) bool {
+		return diff.PredicateChanged[i].ConceptId < diff.PredicateChanged[j].ConceptId
+	})
+	return diff
+}
+
+// groupByConcept groups the given annotations by concept ID dropping duplicated (predicate, concept ID) pairs.
+func groupByConcept(annotations []Annotation) map[string][]Annotation {
+	grouped := make(map[string][]Annotation)
+	seen := make(map[[2]string]struct{})
+	for _, ann := range annotations {
+		key := [2]string{ann.Predicate, ann.ConceptId}
+		if _, found := seen[key]; found {
+			continue
+		}
+		seen[key] = struct{}{}
+		grouped[ann.ConceptId] = append(grouped[ann.ConceptId], ann)
+	}
+	return grouped
+}
+
+func predicates(annotations []Annotation) []string {
+	result := make([]string, 0, len(annotations))
+	for _, ann := range annotations {
+		result = append(result, ann.Predicate)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func equalStrings(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
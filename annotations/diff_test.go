@@ -0,0 +1,53 @@
+package annotations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompare(t *testing.T) {
+	from := []Annotation{
+		{Predicate: about, ConceptId: patchConceptA, PrefLabel: "Kept"},
+		{Predicate: mentions, ConceptId: patchConceptB},
+		{Predicate: mentions, ConceptId: patchConceptC},
+		{Predicate: mentions, ConceptId: patchConceptC},
+	}
+	to := []Annotation{
+		{Predicate: about, ConceptId: patchConceptA},
+		{Predicate: about, ConceptId: patchConceptB},
+		{Predicate: mentions, ConceptId: patchConceptB},
+		{Predicate: about, ConceptId: "http://www.ft.com/thing/5bd49568-6d7c-3c10-a5b0-2f3fd5974a6b"},
+	}
+
+	diff := Compare(from, to)
+
+	assert.False(t, diff.IsEmpty())
+	assert.Equal(t, []Annotation{{Predicate: about, ConceptId: "http://www.ft.com/thing/5bd49568-6d7c-3c10-a5b0-2f3fd5974a6b"}}, diff.Added)
+	assert.Equal(t, []Annotation{{Predicate: mentions, ConceptId: patchConceptC}}, diff.Removed)
+	assert.Equal(t, []PredicateChange{
+		{
+			ConceptId: patchConceptB,
+			From:      []string{mentions},
+			To:        []string{about, mentions},
+		},
+	}, diff.PredicateChanged)
+}
+
+func TestCompareSameAnnotations(t *testing.T) {
+	from := []Annotation{
+		{Predicate: about, ConceptId: patchConceptA, PrefLabel: "Some concept"},
+		{Predicate: mentions, ConceptId: patchConceptB},
+	}
+	to := []Annotation{
+		{Predicate: mentions, ConceptId: patchConceptB},
+		{Predicate: about, ConceptId: patchConceptA},
+	}
+
+	diff := Compare(from, to)
+
+	assert.True(t, diff.IsEmpty())
+	assert.NotNil(t, diff.Added)
+	assert.NotNil(t, diff.Removed)
+	assert.NotNil(t, diff.PredicateChanged)
+}
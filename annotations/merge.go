@@ -0,0 +1,57 @@
+package annotations
+
+import "sort"
+
+// MergeConflict describes a concept whose predicates have been changed in different ways on both sides of a merge.
+type MergeConflict struct {
+	ConceptId string   `json:"id"`
+	Base      []string `json:"base"`
+	Ours      []string `json:"yours"`
+	Theirs    []string `json:"theirs"`
+}
+
+// Merge three-way merges two lists of annotations derived from the same base list,
+// comparing the canonical (predicate, concept ID) pairs of each concept.
+// For each concept, the change made on one side only is kept, as well as the same change made on both sides.
+// A concept whose predicates have been changed in different ways on both sides is reported as a conflict,
+// in which case the merged list should not be used.
+func Merge(base []Annotation, ours []Annotation, theirs []Annotation) ([]Annotation, []MergeConflict) {
+	baseByConcept := groupByConcept(base)
+	oursByConcept := groupByConcept(ours)
+	theirsByConcept := groupByConcept(theirs)
+
+	conceptIDs := make(map[string]struct{})
+	for _, grouped := range []map[string][]Annotation{baseByConcept, oursByConcept, theirsByConcept} {
+		for conceptID := range grouped {
+			conceptIDs[conceptID] = struct{}{}
+		}
+	}
+
+	merged := make([]Annotation, 0)
+	conflicts := make([]MergeConflict, 0)
+	for conceptID := range conceptIDs {
+		basePredicates := predicates(baseByConcept[conceptID])
+		ourPredicates := predicates(oursByConcept[conceptID])
+		theirPredicates := predicates(theirsByConcept[conceptID])
+
+		switch {
+		case equalStrings(ourPredicates, basePredicates):
+			merged = append(merged, theirsByConcept[conceptID]...)
+		case equalStrings(theirPredicates, basePredicates), equalStrings(ourPredicates, theirPredicates):
+			merged = append(merged, oursByConcept[conceptID]...)
+		default:
+			conflicts = append(conflicts, MergeConflict{
+				ConceptId: conceptID,
+				Base:      basePredicates,
+				Ours:      ourPredicates,
+				Theirs:    theirPredicates,
+			})
+		}
+	}
+
+	sort.Sort(NewCanonicalAnnotationSorter(merged))
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].ConceptId < conflicts[j].ConceptId
+	})
+	return merged, conflicts
+}
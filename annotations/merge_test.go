@@ -0,0 +1,63 @@
+package annotations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	conceptD := "http://www.ft.com/thing/5bd49568-6d7c-3c10-a5b0-2f3fd5974a6b"
+
+	base := []Annotation{
+		{Predicate: about, ConceptId: patchConceptA},
+		{Predicate: mentions, ConceptId: patchConceptB},
+		{Predicate: mentions, ConceptId: patchConceptC},
+	}
+	// we remove B and change the predicate of C
+	ours := []Annotation{
+		{Predicate: about, ConceptId: patchConceptA},
+		{Predicate: about, ConceptId: patchConceptC},
+	}
+	// they add D and make the same change to C
+	theirs := []Annotation{
+		{Predicate: about, ConceptId: patchConceptA},
+		{Predicate: mentions, ConceptId: patchConceptB},
+		{Predicate: about, ConceptId: patchConceptC},
+		{Predicate: mentions, ConceptId: conceptD},
+	}
+
+	merged, conflicts := Merge(base, ours, theirs)
+
+	assert.Empty(t, conflicts)
+	assert.Equal(t, []Annotation{
+		{Predicate: about, ConceptId: patchConceptA},
+		{Predicate: about, ConceptId: patchConceptC},
+		{Predicate: mentions, ConceptId: conceptD},
+	}, merged)
+}
+
+func TestMergeConflict(t *testing.T) {
+	base := []Annotation{
+		{Predicate: about, ConceptId: patchConceptA},
+		{Predicate: mentions, ConceptId: patchConceptB},
+	}
+	ours := []Annotation{
+		{Predicate: about, ConceptId: patchConceptA},
+		{Predicate: about, ConceptId: patchConceptB},
+	}
+	theirs := []Annotation{
+		{Predicate: about, ConceptId: patchConceptA},
+	}
+
+	_, conflicts := Merge(base, ours, theirs)
+
+	assert.Equal(t, []MergeConflict{
+		{
+			ConceptId: patchConceptB,
+			Base:      []string{mentions},
+			Ours:      []string{about},
+			Theirs:    []string{},
+		},
+	}, conflicts)
+}
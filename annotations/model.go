@@ -13,8 +13,26 @@ type Annotation struct {
 	Type       string `json:"type,omitempty"`
 	PrefLabel  string `json:"prefLabel,omitempty"`
 	IsFTAuthor bool   `json:"isFTAuthor,omitempty"`
+	// Lifecycle is the UPP annotations lifecycle the annotation comes from, e.g. pac, v1, next-video or v2.
+	Lifecycle string `json:"lifecycle,omitempty"`
+	// Provenance tells whether the annotation has been curated by a human or generated by a machine.
+	Provenance string `json:"provenance,omitempty"`
+	// Source is set on the annotations returned on reads which are not part of the draft.
+	// It is never saved with the draft.
+	Source string `json:"source,omitempty"`
+	// RelevanceScore and ConfidenceScore are the scores of machine-generated annotations, if any.
+	// They are pointers so that a score of 0 is kept, and told apart from a missing score.
+	RelevanceScore  *float64 `json:"relevanceScore,omitempty"`
+	ConfidenceScore *float64 `json:"confidenceScore,omitempty"`
 }
 
+const (
+	// SourceDerived is the source of the implicit annotations UPP derives from the published annotations.
+	SourceDerived = "derived"
+	// SourceSuggestion is the source of the annotations suggested by UPP which are not in the draft.
+	SourceSuggestion = "suggestion"
+)
+
 func userAgent(req *http.Request) {
 	req.Header.Set("User-Agent", "PAC draft-annotations-api")
 }
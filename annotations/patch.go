@@ -0,0 +1,210 @@
+package annotations
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// JSONPatchContentType is the media type of JSON Patch (RFC 6902) documents.
+	JSONPatchContentType = "application/json-patch+json"
+
+	patchPathPrefix = "/annotations/"
+	patchAppendPath = "-"
+
+	patchOpAdd     = "add"
+	patchOpRemove  = "remove"
+	patchOpReplace = "replace"
+	patchOpTest    = "test"
+
+	patchFieldPredicate = "predicate"
+	patchFieldConceptID = "id"
+)
+
+// ErrInvalidPatch is returned when a JSON Patch document cannot be applied to a list of annotations.
+var ErrInvalidPatch = errors.New("invalid JSON patch")
+
+// PatchOperation is a single JSON Patch (RFC 6902) operation on an annotations document.
+// Paths address either a whole annotation (e.g. /annotations/0 or /annotations/- when adding)
+// or its predicate and id fields (e.g. /annotations/0/predicate).
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+type patchPath struct {
+	index  int
+	append bool
+	field  string
+}
+
+// ApplyPatch applies the given operations in order to a copy of the given annotations.
+// The patch is atomic: if any operation fails, an error wrapping ErrInvalidPatch is returned and no result is produced.
+func ApplyPatch(annotations []Annotation, ops []PatchOperation) ([]Annotation, error) {
+	result := make([]Annotation, len(annotations))
+	copy(result, annotations)
+
+	var err error
+	for i, op := range ops {
+		result, err = applyPatchOperation(result, op)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %v: %w", i, op.Op, op.Path, err, ErrInvalidPatch)
+		}
+	}
+	return result, nil
+}
+
+func applyPatchOperation(annotations []Annotation, op PatchOperation) ([]Annotation, error) {
+	path, err := parsePatchPath(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	if path.append && op.Op != patchOpAdd {
+		return nil, errors.New("the append path is only allowed in add operations")
+	}
+	if !path.append {
+		limit := len(annotations)
+		if op.Op == patchOpAdd && path.field == "" {
+			// a new annotation can be inserted right after the last one
+			limit++
+		}
+		if path.index >= limit {
+			return nil, errors.New("index out of range")
+		}
+	}
+
+	switch op.Op {
+	case patchOpAdd:
+		if path.field != "" {
+			return replaceAnnotationField(annotations, path, op.Value)
+		}
+		ann, err := decodePatchAnnotation(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		if path.append {
+			return append(annotations, ann), nil
+		}
+		annotations = append(annotations, Annotation{})
+		copy(annotations[path.index+1:], annotations[path.index:])
+		annotations[path.index] = ann
+		return annotations, nil
+	case patchOpRemove:
+		if path.field != "" {
+			return nil, fmt.Errorf("field %s cannot be removed", path.field)
+		}
+		return append(annotations[:path.index], annotations[path.index+1:]...), nil
+	case patchOpReplace:
+		if path.field != "" {
+			return replaceAnnotationField(annotations, path, op.Value)
+		}
+		ann, err := decodePatchAnnotation(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		annotations[path.index] = ann
+		return annotations, nil
+	case patchOpTest:
+		return annotations, testPatchValue(annotations[path.index], path.field, op.Value)
+	default:
+		return nil, fmt.Errorf("unsupported operation %q", op.Op)
+	}
+}
+
+func parsePatchPath(path string) (patchPath, error) {
+	if !strings.HasPrefix(path, patchPathPrefix) {
+		return patchPath{}, fmt.Errorf("path must start with %s", patchPathPrefix)
+	}
+
+	segments := strings.Split(strings.TrimPrefix(path, patchPathPrefix), "/")
+	if len(segments) > 2 {
+		return patchPath{}, errors.New("path is too deep")
+	}
+
+	var p patchPath
+	if segments[0] == patchAppendPath {
+		p.append = true
+	} else {
+		i, err := strconv.Atoi(segments[0])
+		if err != nil || i < 0 || (len(segments[0]) > 1 && segments[0][0] == '0') {
+			return patchPath{}, fmt.Errorf("invalid array index %q", segments[0])
+		}
+		p.index = i
+	}
+
+	if len(segments) == 2 {
+		if p.append {
+			return patchPath{}, errors.New("the append path cannot address a field")
+		}
+		if segments[1] != patchFieldPredicate && segments[1] != patchFieldConceptID {
+			return patchPath{}, fmt.Errorf("unsupported field %q", segments[1])
+		}
+		p.field = segments[1]
+	}
+	return p, nil
+}
+
+func decodePatchAnnotation(value json.RawMessage) (Annotation, error) {
+	var ann Annotation
+	if len(value) == 0 {
+		return ann, errors.New("missing value")
+	}
+	if err := json.Unmarshal(value, &ann); err != nil {
+		return ann, fmt.Errorf("invalid annotation value: %v", err)
+	}
+	if ann.Predicate == "" || ann.ConceptId == "" {
+		return ann, errors.New("annotation value must have both predicate and id")
+	}
+	return ann, nil
+}
+
+func decodePatchString(value json.RawMessage) (string, error) {
+	var s string
+	if len(value) == 0 {
+		return "", errors.New("missing value")
+	}
+	if err := json.Unmarshal(value, &s); err != nil || s == "" {
+		return "", errors.New("value must be a non empty string")
+	}
+	return s, nil
+}
+
+func replaceAnnotationField(annotations []Annotation, path patchPath, value json.RawMessage) ([]Annotation, error) {
+	s, err := decodePatchString(value)
+	if err != nil {
+		return nil, err
+	}
+	if path.field == patchFieldPredicate {
+		annotations[path.index].Predicate = s
+	} else {
+		annotations[path.index].ConceptId = s
+	}
+	return annotations, nil
+}
+
+func testPatchValue(ann Annotation, field string, value json.RawMessage) error {
+	switch field {
+	case patchFieldPredicate, patchFieldConceptID:
+		s, err := decodePatchString(value)
+		if err != nil {
+			return err
+		}
+		if (field == patchFieldPredicate && ann.Predicate != s) || (field == patchFieldConceptID && ann.ConceptId != s) {
+			return errors.New("test failed")
+		}
+	default:
+		expected, err := decodePatchAnnotation(value)
+		if err != nil {
+			return err
+		}
+		if ann.Predicate != expected.Predicate || ann.ConceptId != expected.ConceptId {
+			return errors.New("test failed")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package annotations
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	patchConceptA = "http://www.ft.com/thing/0a619d71-9af5-3755-90dd-f789b686c67a"
+	patchConceptB = "http://www.ft.com/thing/838b3fbe-efbc-3cfe-b5c0-d38c046492a4"
+	patchConceptC = "http://www.ft.com/thing/9577c6d4-b09e-4552-b88f-e52745abe02b"
+)
+
+func TestApplyPatch(t *testing.T) {
+	original := []Annotation{
+		{Predicate: about, ConceptId: patchConceptA},
+		{Predicate: mentions, ConceptId: patchConceptB},
+	}
+
+	tests := map[string]struct {
+		patch    string
+		expected []Annotation
+	}{
+		"append": {
+			patch: `[{"op":"add","path":"/annotations/-","value":{"predicate":"` + mentions + `","id":"` + patchConceptC + `"}}]`,
+			expected: []Annotation{
+				{Predicate: about, ConceptId: patchConceptA},
+				{Predicate: mentions, ConceptId: patchConceptB},
+				{Predicate: mentions, ConceptId: patchConceptC},
+			},
+		},
+		"insert": {
+			patch: `[{"op":"add","path":"/annotations/1","value":{"predicate":"` + mentions + `","id":"` + patchConceptC + `"}}]`,
+			expected: []Annotation{
+				{Predicate: about, ConceptId: patchConceptA},
+				{Predicate: mentions, ConceptId: patchConceptC},
+				{Predicate: mentions, ConceptId: patchConceptB},
+			},
+		},
+		"insert at the end": {
+			patch: `[{"op":"add","path":"/annotations/2","value":{"predicate":"` + mentions + `","id":"` + patchConceptC + `"}}]`,
+			expected: []Annotation{
+				{Predicate: about, ConceptId: patchConceptA},
+				{Predicate: mentions, ConceptId: patchConceptB},
+				{Predicate: mentions, ConceptId: patchConceptC},
+			},
+		},
+		"remove": {
+			patch: `[{"op":"remove","path":"/annotations/0"}]`,
+			expected: []Annotation{
+				{Predicate: mentions, ConceptId: patchConceptB},
+			},
+		},
+		"replace annotation": {
+			patch: `[{"op":"replace","path":"/annotations/0","value":{"predicate":"` + mentions + `","id":"` + patchConceptC + `"}}]`,
+			expected: []Annotation{
+				{Predicate: mentions, ConceptId: patchConceptC},
+				{Predicate: mentions, ConceptId: patchConceptB},
+			},
+		},
+		"replace predicate": {
+			patch: `[{"op":"replace","path":"/annotations/1/predicate","value":"` + about + `"}]`,
+			expected: []Annotation{
+				{Predicate: about, ConceptId: patchConceptA},
+				{Predicate: about, ConceptId: patchConceptB},
+			},
+		},
+		"test and multiple operations": {
+			patch: `[
+				{"op":"test","path":"/annotations/0/id","value":"` + patchConceptA + `"},
+				{"op":"remove","path":"/annotations/0"},
+				{"op":"test","path":"/annotations/0","value":{"predicate":"` + mentions + `","id":"` + patchConceptB + `"}},
+				{"op":"add","path":"/annotations/-","value":{"predicate":"` + about + `","id":"` + patchConceptC + `"}}
+			]`,
+			expected: []Annotation{
+				{Predicate: mentions, ConceptId: patchConceptB},
+				{Predicate: about, ConceptId: patchConceptC},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var ops []PatchOperation
+			err := json.Unmarshal([]byte(test.patch), &ops)
+			assert.NoError(t, err)
+
+			actual, err := ApplyPatch(original, ops)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+
+			// the original list must not have been altered
+			assert.Equal(t, []Annotation{
+				{Predicate: about, ConceptId: patchConceptA},
+				{Predicate: mentions, ConceptId: patchConceptB},
+			}, original)
+		})
+	}
+}
+
+func TestApplyInvalidPatch(t *testing.T) {
+	original := []Annotation{
+		{Predicate: about, ConceptId: patchConceptA},
+	}
+
+	tests := map[string]string{
+		"unsupported operation":     `[{"op":"move","from":"/annotations/0","path":"/annotations/1"}]`,
+		"invalid path":              `[{"op":"remove","path":"/0"}]`,
+		"invalid index":             `[{"op":"remove","path":"/annotations/01"}]`,
+		"index out of range":        `[{"op":"replace","path":"/annotations/1","value":{"predicate":"` + about + `","id":"` + patchConceptB + `"}}]`,
+		"insert out of range":       `[{"op":"add","path":"/annotations/2","value":{"predicate":"` + about + `","id":"` + patchConceptB + `"}}]`,
+		"remove append path":        `[{"op":"remove","path":"/annotations/-"}]`,
+		"remove field":              `[{"op":"remove","path":"/annotations/0/predicate"}]`,
+		"unsupported field":         `[{"op":"replace","path":"/annotations/0/prefLabel","value":"FT"}]`,
+		"missing value":             `[{"op":"add","path":"/annotations/-"}]`,
+		"incomplete annotation":     `[{"op":"add","path":"/annotations/-","value":{"predicate":"` + about + `"}}]`,
+		"failed test":               `[{"op":"test","path":"/annotations/0/predicate","value":"` + mentions + `"}]`,
+		"failure after valid steps": `[{"op":"remove","path":"/annotations/0"},{"op":"remove","path":"/annotations/0"}]`,
+	}
+
+	for name, patch := range tests {
+		t.Run(name, func(t *testing.T) {
+			var ops []PatchOperation
+			err := json.Unmarshal([]byte(patch), &ops)
+			assert.NoError(t, err)
+
+			actual, err := ApplyPatch(original, ops)
+			assert.True(t, errors.Is(err, ErrInvalidPatch))
+			assert.Nil(t, actual)
+		})
+	}
+}
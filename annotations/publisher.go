@@ -0,0 +1,96 @@
+package annotations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	tidUtils "github.com/Financial-Times/transactionid-utils-go"
+	log "github.com/sirupsen/logrus"
+)
+
+// PublishError is returned when the publish endpoint rejects the annotations.
+type PublishError struct {
+	status int
+	body   []byte
+}
+
+// NewPublishError returns a PublishError for the given status and body returned by the publish endpoint.
+func NewPublishError(status int, body []byte) PublishError {
+	return PublishError{status: status, body: body}
+}
+
+// Error returns the error message.
+func (e PublishError) Error() string {
+	return fmt.Sprintf("publish endpoint returned HTTP status %d", e.status)
+}
+
+// Status returns the http status code returned by the publish endpoint.
+func (e PublishError) Status() int {
+	return e.status
+}
+
+// Body returns the http response body returned by the publish endpoint.
+func (e PublishError) Body() []byte {
+	return e.body
+}
+
+// PublishAPI sends annotations to the downstream publishing endpoint.
+type PublishAPI struct {
+	endpointTemplate string
+	httpClient       *http.Client
+}
+
+// NewPublishAPI initializes PublishAPI by given http client and the url template of the publish endpoint,
+// in which the content UUID replaces the %s verb.
+func NewPublishAPI(client *http.Client, endpointTemplate string) *PublishAPI {
+	return &PublishAPI{endpointTemplate: endpointTemplate, httpClient: client}
+}
+
+// Publish sends the given annotations of the draft with the given hash to the publish endpoint,
+// and returns the status code of its successful response.
+func (p *PublishAPI) Publish(ctx context.Context, contentUUID string, annotations *Annotations, hash string) (int, error) {
+	tid, err := tidUtils.GetTransactionIDFromContext(ctx)
+	if err != nil {
+		tid = tidUtils.NewTransactionID()
+		log.WithField(tidUtils.TransactionIDKey, tid).
+			WithField("uuid", contentUUID).
+			WithError(err).
+			Warn("Transaction ID error in publishing annotations: Generated a new transaction ID")
+		ctx = tidUtils.TransactionAwareContext(ctx, tid)
+	}
+
+	publishLog := log.WithField(tidUtils.TransactionIDKey, tid).WithField("uuid", contentUUID)
+
+	body, err := json.Marshal(annotations)
+	if err != nil {
+		publishLog.WithError(err).Error("Unable to marshall annotations that need to be published")
+		return 0, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf(p.endpointTemplate, contentUUID), bytes.NewBuffer(body))
+	if err != nil {
+		publishLog.WithError(err).Error("Error in creating the HTTP request to the publish endpoint")
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(tidUtils.TransactionIDHeader, tid)
+	req.Header.Set(DocumentHashHeader, hash)
+
+	resp, err := p.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		publishLog.WithError(err).Error("Error making the HTTP request to the publish endpoint")
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		respBody, _ := io.ReadAll(resp.Body)
+		return 0, NewPublishError(resp.StatusCode, respBody)
+	}
+	return resp.StatusCode, nil
+}
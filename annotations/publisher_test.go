@@ -0,0 +1,63 @@
+package annotations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	tidUtils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func newPublishServerMock(t *testing.T, status int, body string, tid string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/content/"+testContentUUID+"/annotations", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, tid, r.Header.Get(tidUtils.TransactionIDHeader))
+		assert.Equal(t, "draft-hash", r.Header.Get(DocumentHashHeader))
+
+		var published Annotations
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&published))
+		assert.Equal(t, expectedCanonicalizedAnnotations, published)
+
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+}
+
+func TestPublish(t *testing.T) {
+	tid := tidUtils.NewTransactionID()
+	s := newPublishServerMock(t, http.StatusAccepted, "", tid)
+	defer s.Close()
+
+	p := NewPublishAPI(testClient, s.URL+"/content/%s/annotations")
+	ctx := tidUtils.TransactionAwareContext(context.Background(), tid)
+	status, err := p.Publish(ctx, testContentUUID, &expectedCanonicalizedAnnotations, "draft-hash")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, status)
+}
+
+func TestPublishRejected(t *testing.T) {
+	tid := tidUtils.NewTransactionID()
+	s := newPublishServerMock(t, http.StatusUnprocessableEntity, `{"message":"invalid annotations"}`, tid)
+	defer s.Close()
+
+	p := NewPublishAPI(testClient, s.URL+"/content/%s/annotations")
+	ctx := tidUtils.TransactionAwareContext(context.Background(), tid)
+	_, err := p.Publish(ctx, testContentUUID, &expectedCanonicalizedAnnotations, "draft-hash")
+
+	var publishErr PublishError
+	assert.True(t, errors.As(err, &publishErr))
+	assert.Equal(t, http.StatusUnprocessableEntity, publishErr.Status())
+	assert.JSONEq(t, `{"message":"invalid annotations"}`, string(publishErr.Body()))
+}
+
+func TestPublishHTTPRequestError(t *testing.T) {
+	p := NewPublishAPI(testClient, ":#")
+	_, err := p.Publish(context.Background(), testContentUUID, &expectedCanonicalizedAnnotations, "draft-hash")
+	assert.Error(t, err)
+}
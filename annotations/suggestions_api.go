@@ -0,0 +1,95 @@
+package annotations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/Financial-Times/draft-annotations-api/mapper"
+	tidUtils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// UPPSuggestionsAPI retrieves the annotations suggested by UPP for a content.
+type UPPSuggestionsAPI struct {
+	endpointTemplate string
+	username         string
+	password         string
+	httpClient       *http.Client
+}
+
+// NewUPPSuggestionsAPI initializes UPPSuggestionsAPI by given http client,
+// the url template of the UPP endpoint for getting suggested annotations and UPP basic auth credentials.
+func NewUPPSuggestionsAPI(client *http.Client, endpoint string, username string, password string) *UPPSuggestionsAPI {
+	return &UPPSuggestionsAPI{endpointTemplate: endpoint, username: username, password: password, httpClient: client}
+}
+
+// GetSuggestions retrieves the list of suggested annotations for given contentUUID, mapped to PAC predicates.
+// The returned annotations have the suggestion source and the confidence score returned by UPP, if any.
+// A content without suggestions has an empty list of suggestions.
+func (api *UPPSuggestionsAPI) GetSuggestions(ctx context.Context, contentUUID string) ([]Annotation, error) {
+	apiReqURI := fmt.Sprintf(api.endpointTemplate, contentUUID)
+
+	tid, err := tidUtils.GetTransactionIDFromContext(ctx)
+	if err != nil {
+		tid = "not_found"
+	}
+
+	logEntry := log.WithField(tidUtils.TransactionIDKey, tid).WithField("url", apiReqURI).WithField("uuid", contentUUID)
+
+	apiReq, err := http.NewRequest("GET", apiReqURI, nil)
+	if err != nil {
+		logEntry.WithError(err).Error("Error in creating the http request")
+		return nil, err
+	}
+
+	apiReq.SetBasicAuth(api.username, api.password)
+	apiReq.Header.Set(tidUtils.TransactionIDHeader, tid)
+	logEntry.Info("Calling UPP Suggestions API")
+
+	resp, err := api.httpClient.Do(apiReq.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read UPP suggestions response body")
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return []Annotation{}, nil
+	default:
+		return nil, UPPError{msg: UPPServiceUnavailableMsg, status: http.StatusServiceUnavailable, uppBody: nil}
+	}
+
+	convertedBody, err := mapper.ConvertPredicates(respBody)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to map predicates from UPP suggestions response")
+	}
+	if convertedBody == nil {
+		return []Annotation{}, nil
+	}
+
+	suggestions := []Annotation{}
+	err = json.Unmarshal(convertedBody, &suggestions)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal UPP suggestions")
+	}
+	for i := range suggestions {
+		suggestions[i].Source = SourceSuggestion
+	}
+
+	return suggestions, nil
+}
+
+// Endpoint retrieves the template for UPP suggestions endpoint
+func (api *UPPSuggestionsAPI) Endpoint() string {
+	return api.endpointTemplate
+}
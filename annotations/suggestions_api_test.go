@@ -0,0 +1,118 @@
+package annotations
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	tidUtils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSuggestions(t *testing.T) {
+	testCases := []struct {
+		name                string
+		suggestionsStatus   int
+		suggestionsBody     string
+		expectedSuggestions []Annotation
+		expectedError       error
+	}{
+		{
+			name:              "happy case",
+			suggestionsStatus: http.StatusOK,
+			suggestionsBody: `[{
+				"predicate": "http://www.ft.com/ontology/annotation/majorMentions",
+				"id": "http://api.ft.com/things/dd158946-e88b-3a85-abe4-5848319501ce",
+				"apiUrl": "http://api.ft.com/things/dd158946-e88b-3a85-abe4-5848319501ce",
+				"types": [
+					"http://www.ft.com/ontology/core/Thing",
+					"http://www.ft.com/ontology/concept/Concept",
+					"http://www.ft.com/ontology/Location"
+				],
+				"prefLabel": "Canada",
+				"confidenceScore": 0.87
+			},
+			{
+				"predicate": "http://www.ft.com/ontology/annotation/mentions",
+				"id": "http://api.ft.com/things/a579350c-61ce-4c00-97ca-ddaa2e0cacf6",
+				"apiUrl": "http://api.ft.com/things/a579350c-61ce-4c00-97ca-ddaa2e0cacf6",
+				"types": [
+					"http://www.ft.com/ontology/core/Thing",
+					"http://www.ft.com/ontology/concept/Concept",
+					"http://www.ft.com/ontology/organisation/Organisation"
+				],
+				"prefLabel": "Acme"
+			}]`,
+			expectedSuggestions: []Annotation{
+				{
+					Predicate:       "http://www.ft.com/ontology/annotation/about",
+					ConceptId:       "http://www.ft.com/thing/dd158946-e88b-3a85-abe4-5848319501ce",
+					ApiUrl:          "http://api.ft.com/things/dd158946-e88b-3a85-abe4-5848319501ce",
+					Type:            "http://www.ft.com/ontology/Location",
+					PrefLabel:       "Canada",
+					Source:          SourceSuggestion,
+					ConfidenceScore: score(0.87),
+				},
+				{
+					Predicate: "http://www.ft.com/ontology/annotation/mentions",
+					ConceptId: "http://www.ft.com/thing/a579350c-61ce-4c00-97ca-ddaa2e0cacf6",
+					ApiUrl:    "http://api.ft.com/things/a579350c-61ce-4c00-97ca-ddaa2e0cacf6",
+					Type:      "http://www.ft.com/ontology/organisation/Organisation",
+					PrefLabel: "Acme",
+					Source:    SourceSuggestion,
+				},
+			},
+		},
+		{
+			name:                "no suggestions",
+			suggestionsStatus:   http.StatusOK,
+			suggestionsBody:     "[]",
+			expectedSuggestions: []Annotation{},
+		},
+		{
+			name:                "not found",
+			suggestionsStatus:   http.StatusNotFound,
+			suggestionsBody:     "",
+			expectedSuggestions: []Annotation{},
+		},
+		{
+			name:              "server error",
+			suggestionsStatus: http.StatusInternalServerError,
+			suggestionsBody:   "",
+			expectedError:     UPPError{msg: UPPServiceUnavailableMsg, status: http.StatusServiceUnavailable, uppBody: nil},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			contentUUID := uuid.New().String()
+			tid := "tid_all-good"
+			ctx := tidUtils.TransactionAwareContext(context.TODO(), tid)
+
+			suggestionsServerMock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/content/"+contentUUID+"/suggestions", r.URL.Path)
+				assert.Equal(t, tid, r.Header.Get(tidUtils.TransactionIDHeader))
+				username, password, ok := r.BasicAuth()
+				assert.True(t, ok)
+				assert.Equal(t, testBasicAuthUsername, username)
+				assert.Equal(t, testBasicAuthPassword, password)
+
+				w.WriteHeader(test.suggestionsStatus)
+				_, _ = w.Write([]byte(test.suggestionsBody))
+			}))
+			defer suggestionsServerMock.Close()
+
+			suggestionsAPI := NewUPPSuggestionsAPI(testClient, suggestionsServerMock.URL+"/content/%v/suggestions", testBasicAuthUsername, testBasicAuthPassword)
+			suggestions, err := suggestionsAPI.GetSuggestions(ctx, contentUUID)
+
+			if test.expectedError != nil {
+				assert.EqualValues(t, test.expectedError, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedSuggestions, suggestions)
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/Financial-Times/draft-annotations-api/annotations"
+)
+
+// Entry records a change made by an editor to the draft annotations of a content.
+// Changes is the canonical diff between the draft before and after the change,
+// and is not set when the annotations before the change could not be read.
+// Hash is not set when the draft has been discarded.
+type Entry struct {
+	Timestamp     time.Time         `json:"timestamp"`
+	TransactionID string            `json:"transactionId"`
+	ContentUUID   string            `json:"uuid"`
+	Editor        string            `json:"editor"`
+	Operation     string            `json:"operation"`
+	PreviousHash  string            `json:"previousHash,omitempty"`
+	Hash          string            `json:"hash,omitempty"`
+	Changes       *annotations.Diff `json:"changes,omitempty"`
+}
+
+// Sink records the audit entries of the changes to the draft annotations.
+type Sink interface {
+	// Record stores the given audit entry.
+	Record(ctx context.Context, entry Entry) error
+}
+
+// Reader reads back the recorded audit entries.
+type Reader interface {
+	// Entries returns the audit entries of the given content, oldest first.
+	Entries(ctx context.Context, contentUUID string) ([]Entry, error)
+}
+
+// Trail is a Sink whose entries can be read back.
+type Trail interface {
+	Sink
+	Reader
+}
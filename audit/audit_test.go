@@ -0,0 +1,79 @@
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Financial-Times/draft-annotations-api/annotations"
+	"github.com/stretchr/testify/assert"
+)
+
+const testContentUUID = "83a201c6-60cd-11e7-91a7-502f7ee26895"
+
+var testTime = time.Date(2023, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+func testEntries() []Entry {
+	return []Entry{
+		{
+			Timestamp:     testTime,
+			TransactionID: "tid_1",
+			ContentUUID:   testContentUUID,
+			Editor:        "jane.doe",
+			Operation:     "PUT",
+			Hash:          "hash-1",
+			Changes: &annotations.Diff{
+				Added: []annotations.Annotation{
+					{Predicate: "http://www.ft.com/ontology/annotation/about", ConceptId: "http://www.ft.com/thing/0a619d71-9af5-3755-90dd-f789b686c67a"},
+				},
+				Removed:          []annotations.Annotation{},
+				PredicateChanged: []annotations.PredicateChange{},
+			},
+		},
+		{
+			Timestamp:     testTime.Add(time.Hour),
+			TransactionID: "tid_2",
+			ContentUUID:   testContentUUID,
+			Editor:        "methode-web-pub",
+			Operation:     "DELETE",
+			PreviousHash:  "hash-1",
+			Hash:          "hash-2",
+			Changes: &annotations.Diff{
+				Added: []annotations.Annotation{},
+				Removed: []annotations.Annotation{
+					{Predicate: "http://www.ft.com/ontology/annotation/about", ConceptId: "http://www.ft.com/thing/0a619d71-9af5-3755-90dd-f789b686c67a"},
+				},
+				PredicateChanged: []annotations.PredicateChange{},
+			},
+		},
+		{
+			Timestamp:     testTime.Add(2 * time.Hour),
+			TransactionID: "tid_3",
+			ContentUUID:   testContentUUID,
+			Editor:        "jane.doe",
+			Operation:     "PATCH",
+			PreviousHash:  "hash-2",
+			Hash:          "hash-3",
+		},
+	}
+}
+
+// testTrail checks the behaviour shared by all Trail implementations.
+func testTrail(t *testing.T, s Trail) {
+	ctx := context.Background()
+
+	entries, err := s.Entries(ctx, testContentUUID)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+
+	for _, e := range testEntries() {
+		err = s.Record(ctx, e)
+		assert.NoError(t, err)
+	}
+	err = s.Record(ctx, Entry{ContentUUID: "another-content", Hash: "another-hash", Timestamp: testTime})
+	assert.NoError(t, err)
+
+	entries, err = s.Entries(ctx, testContentUUID)
+	assert.NoError(t, err)
+	assert.Equal(t, testEntries(), entries)
+}
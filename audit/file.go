@@ -0,0 +1,49 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Financial-Times/draft-annotations-api/internal/jsonl"
+)
+
+type fileSink struct {
+	sync.Mutex
+	dir string
+}
+
+// NewFileSink returns a Trail appending the audit entries of each content to a JSON lines file
+// in the given directory, which is created if it does not exist. The entries are never removed.
+func NewFileSink(dir string) (Trail, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating audit directory: %w", err)
+	}
+	return &fileSink{dir: dir}, nil
+}
+
+func (s *fileSink) Record(_ context.Context, entry Entry) error {
+	s.Lock()
+	defer s.Unlock()
+
+	return jsonl.Append(s.path(entry.ContentUUID), entry)
+}
+
+func (s *fileSink) Entries(_ context.Context, contentUUID string) ([]Entry, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	entries, err := jsonl.Read[Entry](s.path(contentUUID))
+	if err != nil {
+		return nil, fmt.Errorf("reading audit trail of %s: %w", contentUUID, err)
+	}
+	return entries, nil
+}
+
+// path returns the file holding the audit entries of the given content.
+// Only the base name of the content UUID is used, so that it cannot point outside of the audit directory.
+func (s *fileSink) path(contentUUID string) string {
+	return filepath.Join(s.dir, filepath.Base(contentUUID)+".jsonl")
+}
@@ -0,0 +1,43 @@
+package audit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileSink(t *testing.T) {
+	s, err := NewFileSink(filepath.Join(t.TempDir(), "audit"))
+	assert.NoError(t, err)
+	testTrail(t, s)
+}
+
+func TestFileSinkPersistsEntries(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	s, err := NewFileSink(dir)
+	assert.NoError(t, err)
+	err = s.Record(ctx, testEntries()[0])
+	assert.NoError(t, err)
+
+	reopened, err := NewFileSink(dir)
+	assert.NoError(t, err)
+	entries, err := reopened.Entries(ctx, testContentUUID)
+	assert.NoError(t, err)
+	assert.Equal(t, testEntries()[:1], entries)
+}
+
+func TestFileSinkCorruptedFile(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, testContentUUID+".jsonl"), []byte("not json\n"), 0o644)
+	assert.NoError(t, err)
+
+	s, err := NewFileSink(dir)
+	assert.NoError(t, err)
+	_, err = s.Entries(context.Background(), testContentUUID)
+	assert.Error(t, err)
+}
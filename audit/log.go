@@ -0,0 +1,28 @@
+package audit
+
+import (
+	"context"
+
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	log "github.com/sirupsen/logrus"
+)
+
+type logSink struct{}
+
+// NewLogSink returns a Sink writing the audit entries to the application log.
+// The entries cannot be read back through the API.
+func NewLogSink() Sink {
+	return logSink{}
+}
+
+func (logSink) Record(_ context.Context, entry Entry) error {
+	log.WithField(tidutils.TransactionIDKey, entry.TransactionID).
+		WithField("uuid", entry.ContentUUID).
+		WithField("editor", entry.Editor).
+		WithField("operation", entry.Operation).
+		WithField("previousHash", entry.PreviousHash).
+		WithField("hash", entry.Hash).
+		WithField("changes", entry.Changes).
+		Info("Draft annotations changed")
+	return nil
+}
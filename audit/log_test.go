@@ -0,0 +1,25 @@
+package audit
+
+import (
+	"context"
+	"testing"
+
+	logTest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogSink(t *testing.T) {
+	hook := logTest.NewGlobal()
+	entry := testEntries()[1]
+
+	err := NewLogSink().Record(context.Background(), entry)
+	assert.NoError(t, err)
+
+	assert.Len(t, hook.Entries, 1)
+	logEntry := hook.LastEntry()
+	assert.Equal(t, "Draft annotations changed", logEntry.Message)
+	assert.Equal(t, "methode-web-pub", logEntry.Data["editor"])
+	assert.Equal(t, "DELETE", logEntry.Data["operation"])
+	assert.Equal(t, testContentUUID, logEntry.Data["uuid"])
+	assert.Equal(t, "hash-2", logEntry.Data["hash"])
+}
@@ -0,0 +1,34 @@
+package audit
+
+import (
+	"context"
+	"sync"
+)
+
+type memorySink struct {
+	sync.RWMutex
+	entries map[string][]Entry
+}
+
+// NewMemorySink returns a Trail keeping the audit entries in memory,
+// which are lost when the service restarts.
+func NewMemorySink() Trail {
+	return &memorySink{entries: make(map[string][]Entry)}
+}
+
+func (s *memorySink) Record(_ context.Context, entry Entry) error {
+	s.Lock()
+	defer s.Unlock()
+
+	s.entries[entry.ContentUUID] = append(s.entries[entry.ContentUUID], entry)
+	return nil
+}
+
+func (s *memorySink) Entries(_ context.Context, contentUUID string) ([]Entry, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	entries := make([]Entry, len(s.entries[contentUUID]))
+	copy(entries, s.entries[contentUUID])
+	return entries, nil
+}
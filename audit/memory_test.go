@@ -0,0 +1,27 @@
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemorySink(t *testing.T) {
+	testTrail(t, NewMemorySink())
+}
+
+func TestMemorySinkEntriesReturnsCopy(t *testing.T) {
+	s := NewMemorySink()
+	ctx := context.Background()
+	err := s.Record(ctx, Entry{ContentUUID: testContentUUID, Hash: "hash-1"})
+	assert.NoError(t, err)
+
+	entries, err := s.Entries(ctx, testContentUUID)
+	assert.NoError(t, err)
+	entries[0].Hash = "changed"
+
+	entries, err = s.Entries(ctx, testContentUUID)
+	assert.NoError(t, err)
+	assert.Equal(t, "hash-1", entries[0].Hash)
+}
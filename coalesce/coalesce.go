@@ -0,0 +1,69 @@
+package coalesce
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+)
+
+type call[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// Group coalesces the concurrent calls with the same key, so that they share one call and its result.
+// The zero value is ready to use, with no timeout for the calls.
+type Group[V any] struct {
+	// Timeout bounds the duration of each call, which does not depend on the deadlines of its callers.
+	Timeout time.Duration
+
+	lock  sync.Mutex
+	calls map[string]*call[V]
+}
+
+// Do calls fn for the given key, unless a call with the same key is in flight, in which case it waits for its result.
+// The call does not belong to any of its callers: it is made with a context detached from the context of the caller
+// starting it, with a transaction ID of its own and the timeout of the group, so that the other callers are not affected
+// by the transaction ID, the deadline or the cancellation of that caller.
+// Each caller stops waiting when its own context is done. The returned flag reports whether the result has been shared
+// with a call in flight.
+func (g *Group[V]) Do(ctx context.Context, key string, fn func(context.Context) (V, error)) (V, bool, error) {
+	g.lock.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*call[V])
+	}
+	c, shared := g.calls[key]
+	if !shared {
+		c = &call[V]{done: make(chan struct{})}
+		g.calls[key] = c
+		go g.run(key, c, fn)
+	}
+	g.lock.Unlock()
+
+	select {
+	case <-c.done:
+		return c.value, shared, c.err
+	case <-ctx.Done():
+		var zero V
+		return zero, shared, ctx.Err()
+	}
+}
+
+func (g *Group[V]) run(key string, c *call[V], fn func(context.Context) (V, error)) {
+	callCtx := tidutils.TransactionAwareContext(context.Background(), tidutils.NewTransactionID())
+	if g.Timeout > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(callCtx, g.Timeout)
+		defer cancel()
+	}
+
+	c.value, c.err = fn(callCtx)
+
+	g.lock.Lock()
+	delete(g.calls, key)
+	g.lock.Unlock()
+	close(c.done)
+}
@@ -0,0 +1,178 @@
+package coalesce
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDoSharesConcurrentCalls(t *testing.T) {
+	var g Group[string]
+	var calls int32
+	release := make(chan struct{})
+	fn := func(context.Context) (string, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return "result", nil
+	}
+
+	var wg sync.WaitGroup
+	var sharedCount int32
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, shared, err := g.Do(context.Background(), "key", fn)
+			assert.NoError(t, err)
+			assert.Equal(t, "result", v)
+			if shared {
+				atomic.AddInt32(&sharedCount, 1)
+			}
+		}()
+	}
+
+	assert.Eventually(t, func() bool {
+		g.lock.Lock()
+		defer g.lock.Unlock()
+		return len(g.calls) == 1
+	}, time.Second, time.Millisecond)
+	// let the other callers join the call in flight
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	assert.Equal(t, int32(4), atomic.LoadInt32(&sharedCount))
+}
+
+func TestDoDoesNotShareDifferentKeys(t *testing.T) {
+	var g Group[string]
+
+	a, sharedA, err := g.Do(context.Background(), "a", func(context.Context) (string, error) { return "a", nil })
+	assert.NoError(t, err)
+	b, sharedB, err := g.Do(context.Background(), "b", func(context.Context) (string, error) { return "b", nil })
+	assert.NoError(t, err)
+
+	assert.Equal(t, "a", a)
+	assert.Equal(t, "b", b)
+	assert.False(t, sharedA)
+	assert.False(t, sharedB)
+}
+
+func TestDoDoesNotKeepResults(t *testing.T) {
+	var g Group[int]
+	var calls int
+
+	for i := 0; i < 2; i++ {
+		_, shared, err := g.Do(context.Background(), "key", func(context.Context) (int, error) {
+			calls++
+			return calls, errors.New("failed")
+		})
+		assert.EqualError(t, err, "failed")
+		assert.False(t, shared)
+	}
+	assert.Equal(t, 2, calls)
+}
+
+func TestDoCallerCancellation(t *testing.T) {
+	var g Group[string]
+	release := make(chan struct{})
+	callErr := make(chan error, 1)
+	fn := func(ctx context.Context) (string, error) {
+		<-release
+		callErr <- ctx.Err()
+		return "result", nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _, err := g.Do(ctx, "key", fn)
+		assert.ErrorIs(t, err, context.Canceled)
+	}()
+
+	assert.Eventually(t, func() bool {
+		g.lock.Lock()
+		defer g.lock.Unlock()
+		return len(g.calls) == 1
+	}, time.Second, time.Millisecond)
+
+	second := make(chan string)
+	go func() {
+		v, shared, err := g.Do(context.Background(), "key", fn)
+		assert.NoError(t, err)
+		assert.True(t, shared)
+		second <- v
+	}()
+
+	// the first caller goes away, the call carries on for the second one
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	<-done
+	close(release)
+
+	assert.Equal(t, "result", <-second)
+	assert.NoError(t, <-callErr)
+}
+
+func TestDoDetachesCallFromCallers(t *testing.T) {
+	var g Group[string]
+	release := make(chan struct{})
+	callCtx := make(chan context.Context, 1)
+	fn := func(ctx context.Context) (string, error) {
+		callCtx <- ctx
+		<-release
+		return "result", nil
+	}
+
+	// the first caller has a short deadline, the second one a longer one
+	first, cancelFirst := context.WithTimeout(tidutils.TransactionAwareContext(context.Background(), "tid_first"), 20*time.Millisecond)
+	defer cancelFirst()
+	second, cancelSecond := context.WithTimeout(tidutils.TransactionAwareContext(context.Background(), "tid_second"), time.Second)
+	defer cancelSecond()
+
+	firstErr := make(chan error, 1)
+	go func() {
+		_, _, err := g.Do(first, "key", fn)
+		firstErr <- err
+	}()
+	ctx := <-callCtx
+
+	secondResult := make(chan string, 1)
+	go func() {
+		v, shared, err := g.Do(second, "key", fn)
+		assert.NoError(t, err)
+		assert.True(t, shared)
+		secondResult <- v
+	}()
+
+	assert.ErrorIs(t, <-firstErr, context.DeadlineExceeded)
+	close(release)
+	assert.Equal(t, "result", <-secondResult)
+
+	assert.NoError(t, ctx.Err())
+	_, hasDeadline := ctx.Deadline()
+	assert.False(t, hasDeadline)
+	tid, err := tidutils.GetTransactionIDFromContext(ctx)
+	assert.NoError(t, err)
+	assert.NotEqual(t, "tid_first", tid)
+	assert.NotEqual(t, "tid_second", tid)
+}
+
+func TestDoTimeout(t *testing.T) {
+	g := Group[string]{Timeout: 10 * time.Millisecond}
+
+	_, _, err := g.Do(context.Background(), "key", func(ctx context.Context) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	})
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
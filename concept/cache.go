@@ -0,0 +1,190 @@
+package concept
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+// CacheConfig configures the concepts cache.
+type CacheConfig struct {
+	// TTL is how long a concept is served from the cache before being read again.
+	TTL time.Duration
+	// NegativeTTL is how long an unknown concept ID is remembered as such before being read again.
+	NegativeTTL time.Duration
+	// MaxSize is the number of concept IDs kept in the cache, after which the least recently used ones are evicted.
+	MaxSize int
+}
+
+type cacheEntry struct {
+	uuid    string
+	concept Concept
+	found   bool
+	expires time.Time
+}
+
+// CachedReader is implemented by the ReadAPIs which can return the concepts they hold without reading them.
+type CachedReader interface {
+	// GetCachedConceptsByIDs returns the cached concepts with the given IDs, even if they have expired.
+	GetCachedConceptsByIDs(conceptIDs []string) map[string]Concept
+}
+
+// cachedReadAPI is a ReadAPI serving the concepts from a TTL and LRU bound cache,
+// and reading only the missing ones from the decorated ReadAPI.
+type cachedReadAPI struct {
+	api    ReadAPI
+	config CacheConfig
+	now    func() time.Time
+
+	lock    sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+
+	hits    metrics.Counter
+	misses  metrics.Counter
+	evicted metrics.Counter
+}
+
+// NewCachedReadAPI returns a ReadAPI caching the concepts read by the given one,
+// and registers the cache metrics in the given registry.
+// The concept IDs which are not found are cached as well, so that they are not read again until NegativeTTL expires.
+// When the given ReadAPI returns partial results, the concepts fetched are cached and returned with its error.
+func NewCachedReadAPI(api ReadAPI, config CacheConfig, registry metrics.Registry) ReadAPI {
+	return &cachedReadAPI{
+		api:     api,
+		config:  config,
+		now:     time.Now,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+		hits:    metrics.GetOrRegisterCounter("concept.cache.hit", registry),
+		misses:  metrics.GetOrRegisterCounter("concept.cache.miss", registry),
+		evicted: metrics.GetOrRegisterCounter("concept.cache.evicted", registry),
+	}
+}
+
+func (c *cachedReadAPI) GetConceptsByIDs(ctx context.Context, conceptIDs []string) (map[string]Concept, error) {
+	result := make(map[string]Concept)
+	missing := c.lookup(conceptIDs, result)
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	concepts, err := c.api.GetConceptsByIDs(ctx, missing)
+	var partialErr *PartialResultsError
+	if err != nil && !errors.As(err, &partialErr) {
+		return nil, err
+	}
+
+	// the IDs of the failed batches are not known to be unknown, so they are not cached
+	fetched := missing
+	if partialErr != nil {
+		fetched = withoutIDs(missing, partialErr.FailedIDs())
+	}
+	c.store(fetched, concepts)
+	for uuid, concept := range concepts {
+		result[uuid] = concept
+	}
+	return result, err
+}
+
+// lookup adds the cached concepts to the result and returns the IDs which are not cached or have expired.
+func (c *cachedReadAPI) lookup(conceptIDs []string, result map[string]Concept) []string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.now()
+	var missing []string
+	seen := make(map[string]struct{}, len(conceptIDs))
+	for _, uuid := range conceptIDs {
+		if _, ok := seen[uuid]; ok {
+			continue
+		}
+		seen[uuid] = struct{}{}
+
+		element, ok := c.entries[uuid]
+		if !ok || now.After(element.Value.(*cacheEntry).expires) {
+			c.misses.Inc(1)
+			missing = append(missing, uuid)
+			continue
+		}
+
+		c.hits.Inc(1)
+		c.lru.MoveToFront(element)
+		if entry := element.Value.(*cacheEntry); entry.found {
+			result[uuid] = entry.concept
+		}
+	}
+	return missing
+}
+
+// store caches the concepts read for the given IDs, and the IDs which have not been found.
+func (c *cachedReadAPI) store(conceptIDs []string, concepts map[string]Concept) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.now()
+	for _, uuid := range conceptIDs {
+		concept, found := concepts[uuid]
+		entry := &cacheEntry{uuid: uuid, concept: concept, found: found, expires: now.Add(c.config.TTL)}
+		if !found {
+			entry.expires = now.Add(c.config.NegativeTTL)
+		}
+
+		if element, ok := c.entries[uuid]; ok {
+			element.Value = entry
+			c.lru.MoveToFront(element)
+			continue
+		}
+		c.entries[uuid] = c.lru.PushFront(entry)
+	}
+
+	for c.config.MaxSize > 0 && c.lru.Len() > c.config.MaxSize {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).uuid)
+		c.evicted.Inc(1)
+	}
+}
+
+// GetCachedConceptsByIDs returns the cached concepts with the given IDs, including the expired ones,
+// so that they can be used when the concepts cannot be read. It does not change the cache nor its metrics.
+func (c *cachedReadAPI) GetCachedConceptsByIDs(conceptIDs []string) map[string]Concept {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	result := make(map[string]Concept)
+	for _, uuid := range conceptIDs {
+		if element, ok := c.entries[uuid]; ok {
+			if entry := element.Value.(*cacheEntry); entry.found {
+				result[uuid] = entry.concept
+			}
+		}
+	}
+	return result
+}
+
+func withoutIDs(ids []string, excluded []string) []string {
+	excludedSet := make(map[string]struct{}, len(excluded))
+	for _, id := range excluded {
+		excludedSet[id] = struct{}{}
+	}
+	var result []string
+	for _, id := range ids {
+		if _, ok := excludedSet[id]; !ok {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+func (c *cachedReadAPI) Endpoint() string {
+	return c.api.Endpoint()
+}
+
+func (c *cachedReadAPI) GTG() error {
+	return c.api.GTG()
+}
@@ -0,0 +1,194 @@
+package concept
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	cacheConceptA = "0a619d71-9af5-3755-90dd-f789b686c67a"
+	cacheConceptB = "838b3fbe-efbc-3cfe-b5c0-d38c046492a4"
+	cacheConceptC = "4f50b156-6c50-4693-b835-02f70d3f3bc0"
+)
+
+type fakeReadAPI struct {
+	concepts map[string]Concept
+	err      error
+	requests [][]string
+}
+
+func (f *fakeReadAPI) GetConceptsByIDs(_ context.Context, ids []string) (map[string]Concept, error) {
+	f.requests = append(f.requests, ids)
+	if f.err != nil {
+		return nil, f.err
+	}
+	result := make(map[string]Concept)
+	for _, id := range ids {
+		if c, ok := f.concepts[id]; ok {
+			result[id] = c
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeReadAPI) Endpoint() string {
+	return "http://concepts"
+}
+
+func (f *fakeReadAPI) GTG() error {
+	return f.err
+}
+
+func newFakeReadAPI() *fakeReadAPI {
+	return &fakeReadAPI{concepts: map[string]Concept{
+		cacheConceptA: {ID: "http://www.ft.com/thing/" + cacheConceptA, PrefLabel: "A"},
+		cacheConceptB: {ID: "http://www.ft.com/thing/" + cacheConceptB, PrefLabel: "B"},
+	}}
+}
+
+func newTestCache(api ReadAPI, config CacheConfig, registry metrics.Registry) (*cachedReadAPI, *time.Time) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewCachedReadAPI(api, config, registry).(*cachedReadAPI)
+	c.now = func() time.Time { return now }
+	return c, &now
+}
+
+func TestCachedReadAPIServesCachedConcepts(t *testing.T) {
+	api := newFakeReadAPI()
+	registry := metrics.NewRegistry()
+	c, _ := newTestCache(api, CacheConfig{TTL: time.Minute, NegativeTTL: time.Minute, MaxSize: 10}, registry)
+
+	concepts, err := c.GetConceptsByIDs(context.Background(), []string{cacheConceptA})
+	assert.NoError(t, err)
+	assert.Equal(t, "A", concepts[cacheConceptA].PrefLabel)
+
+	concepts, err = c.GetConceptsByIDs(context.Background(), []string{cacheConceptA, cacheConceptB})
+	assert.NoError(t, err)
+	assert.Len(t, concepts, 2)
+	assert.Equal(t, "A", concepts[cacheConceptA].PrefLabel)
+	assert.Equal(t, "B", concepts[cacheConceptB].PrefLabel)
+
+	assert.Equal(t, [][]string{{cacheConceptA}, {cacheConceptB}}, api.requests)
+	assert.Equal(t, int64(1), metrics.GetOrRegisterCounter("concept.cache.hit", registry).Count())
+	assert.Equal(t, int64(2), metrics.GetOrRegisterCounter("concept.cache.miss", registry).Count())
+}
+
+func TestCachedReadAPIExpiresConcepts(t *testing.T) {
+	api := newFakeReadAPI()
+	c, now := newTestCache(api, CacheConfig{TTL: time.Minute, NegativeTTL: time.Minute, MaxSize: 10}, metrics.NewRegistry())
+
+	_, err := c.GetConceptsByIDs(context.Background(), []string{cacheConceptA})
+	assert.NoError(t, err)
+
+	*now = now.Add(30 * time.Second)
+	_, err = c.GetConceptsByIDs(context.Background(), []string{cacheConceptA})
+	assert.NoError(t, err)
+	assert.Len(t, api.requests, 1)
+
+	*now = now.Add(time.Minute)
+	concepts, err := c.GetConceptsByIDs(context.Background(), []string{cacheConceptA})
+	assert.NoError(t, err)
+	assert.Equal(t, "A", concepts[cacheConceptA].PrefLabel)
+	assert.Len(t, api.requests, 2)
+}
+
+func TestCachedReadAPICachesUnknownConcepts(t *testing.T) {
+	api := newFakeReadAPI()
+	c, now := newTestCache(api, CacheConfig{TTL: time.Hour, NegativeTTL: time.Minute, MaxSize: 10}, metrics.NewRegistry())
+
+	concepts, err := c.GetConceptsByIDs(context.Background(), []string{cacheConceptC})
+	assert.NoError(t, err)
+	assert.Empty(t, concepts)
+
+	concepts, err = c.GetConceptsByIDs(context.Background(), []string{cacheConceptC})
+	assert.NoError(t, err)
+	assert.Empty(t, concepts)
+	assert.Len(t, api.requests, 1)
+
+	*now = now.Add(2 * time.Minute)
+	_, err = c.GetConceptsByIDs(context.Background(), []string{cacheConceptC})
+	assert.NoError(t, err)
+	assert.Len(t, api.requests, 2)
+}
+
+func TestCachedReadAPIEvictsLeastRecentlyUsed(t *testing.T) {
+	api := newFakeReadAPI()
+	registry := metrics.NewRegistry()
+	c, _ := newTestCache(api, CacheConfig{TTL: time.Hour, NegativeTTL: time.Hour, MaxSize: 2}, registry)
+
+	_, err := c.GetConceptsByIDs(context.Background(), []string{cacheConceptA, cacheConceptB})
+	assert.NoError(t, err)
+	// reading A makes B the least recently used
+	_, err = c.GetConceptsByIDs(context.Background(), []string{cacheConceptA})
+	assert.NoError(t, err)
+	_, err = c.GetConceptsByIDs(context.Background(), []string{cacheConceptC})
+	assert.NoError(t, err)
+
+	_, err = c.GetConceptsByIDs(context.Background(), []string{cacheConceptA, cacheConceptB})
+	assert.NoError(t, err)
+
+	assert.Equal(t, [][]string{{cacheConceptA, cacheConceptB}, {cacheConceptC}, {cacheConceptB}}, api.requests)
+	assert.Equal(t, int64(2), metrics.GetOrRegisterCounter("concept.cache.evicted", registry).Count())
+}
+
+func TestCachedReadAPIDoesNotCacheErrors(t *testing.T) {
+	api := newFakeReadAPI()
+	api.err = errors.New("concepts unavailable")
+	c, _ := newTestCache(api, CacheConfig{TTL: time.Hour, NegativeTTL: time.Hour, MaxSize: 10}, metrics.NewRegistry())
+
+	_, err := c.GetConceptsByIDs(context.Background(), []string{cacheConceptA})
+	assert.EqualError(t, err, "concepts unavailable")
+
+	api.err = nil
+	concepts, err := c.GetConceptsByIDs(context.Background(), []string{cacheConceptA})
+	assert.NoError(t, err)
+	assert.Equal(t, "A", concepts[cacheConceptA].PrefLabel)
+	assert.Len(t, api.requests, 2)
+}
+
+type partialReadAPI struct {
+	fakeReadAPI
+	failedID string
+}
+
+func (p *partialReadAPI) GetConceptsByIDs(ctx context.Context, ids []string) (map[string]Concept, error) {
+	concepts, _ := p.fakeReadAPI.GetConceptsByIDs(ctx, ids)
+	delete(concepts, p.failedID)
+	return concepts, &PartialResultsError{Failed: []BatchError{{IDs: []string{p.failedID}, Err: ErrUnexpectedResponse}}}
+}
+
+func TestCachedReadAPIPartialResults(t *testing.T) {
+	api := &partialReadAPI{fakeReadAPI: *newFakeReadAPI(), failedID: cacheConceptB}
+	c, _ := newTestCache(api, CacheConfig{TTL: time.Hour, NegativeTTL: time.Hour, MaxSize: 10}, metrics.NewRegistry())
+
+	concepts, err := c.GetConceptsByIDs(context.Background(), []string{cacheConceptA, cacheConceptB, cacheConceptC})
+	var partialErr *PartialResultsError
+	assert.True(t, errors.As(err, &partialErr))
+	assert.Equal(t, map[string]Concept{cacheConceptA: api.concepts[cacheConceptA]}, concepts)
+
+	// A and the unknown C are cached, but not B whose batch has failed
+	_, _ = c.GetConceptsByIDs(context.Background(), []string{cacheConceptA, cacheConceptB, cacheConceptC})
+	assert.Equal(t, [][]string{{cacheConceptA, cacheConceptB, cacheConceptC}, {cacheConceptB}}, api.requests)
+}
+
+func TestCachedReadAPIGetCachedConceptsByIDs(t *testing.T) {
+	api := newFakeReadAPI()
+	registry := metrics.NewRegistry()
+	c, now := newTestCache(api, CacheConfig{TTL: time.Minute, NegativeTTL: time.Minute, MaxSize: 10}, registry)
+
+	_, err := c.GetConceptsByIDs(context.Background(), []string{cacheConceptA, cacheConceptC})
+	assert.NoError(t, err)
+
+	// the expired concepts are returned as well, but not the unknown ones
+	*now = now.Add(time.Hour)
+	cached := c.GetCachedConceptsByIDs([]string{cacheConceptA, cacheConceptB, cacheConceptC})
+	assert.Equal(t, map[string]Concept{cacheConceptA: api.concepts[cacheConceptA]}, cached)
+
+	assert.Len(t, api.requests, 1)
+	assert.Equal(t, int64(0), metrics.GetOrRegisterCounter("concept.cache.hit", registry).Count())
+}
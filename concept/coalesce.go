@@ -0,0 +1,54 @@
+package concept
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Financial-Times/draft-annotations-api/coalesce"
+)
+
+type readResult struct {
+	concepts map[string]Concept
+	err      error
+}
+
+// coalescingReadAPI is a ReadAPI sharing one read of the concepts between the concurrent reads of the same concept IDs.
+type coalescingReadAPI struct {
+	ReadAPI
+	reads coalesce.Group[readResult]
+}
+
+// NewCoalescingReadAPI returns a ReadAPI coalescing the concurrent reads of the same set of concept IDs,
+// in any order, into one read of the given ReadAPI, bound by the given timeout.
+func NewCoalescingReadAPI(api ReadAPI, timeout time.Duration) ReadAPI {
+	return &coalescingReadAPI{ReadAPI: api, reads: coalesce.Group[readResult]{Timeout: timeout}}
+}
+
+func (c *coalescingReadAPI) GetConceptsByIDs(ctx context.Context, conceptIDs []string) (map[string]Concept, error) {
+	result, _, err := c.reads.Do(ctx, conceptIDsKey(conceptIDs), func(ctx context.Context) (readResult, error) {
+		concepts, err := c.ReadAPI.GetConceptsByIDs(ctx, conceptIDs)
+		// partial results are returned along with their error, so it is shared as part of the result
+		return readResult{concepts: concepts, err: err}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the callers sharing the read get their own copy of the concepts
+	var concepts map[string]Concept
+	if result.concepts != nil {
+		concepts = make(map[string]Concept, len(result.concepts))
+		for uuid, concept := range result.concepts {
+			concepts[uuid] = concept
+		}
+	}
+	return concepts, result.err
+}
+
+func conceptIDsKey(conceptIDs []string) string {
+	ids := append([]string(nil), conceptIDs...)
+	sort.Strings(ids)
+	return strings.Join(ids, ",")
+}
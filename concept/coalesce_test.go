@@ -0,0 +1,70 @@
+package concept
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type blockingReadAPI struct {
+	fakeReadAPI
+	reads   int32
+	release chan struct{}
+}
+
+func (b *blockingReadAPI) GetConceptsByIDs(ctx context.Context, ids []string) (map[string]Concept, error) {
+	atomic.AddInt32(&b.reads, 1)
+	<-b.release
+	return b.fakeReadAPI.GetConceptsByIDs(ctx, ids)
+}
+
+func TestCoalescingReadAPI(t *testing.T) {
+	api := &blockingReadAPI{fakeReadAPI: *newFakeReadAPI(), release: make(chan struct{})}
+	c := NewCoalescingReadAPI(api, time.Second)
+
+	var wg sync.WaitGroup
+	results := make([]map[string]Concept, 4)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ids := []string{cacheConceptA, cacheConceptB}
+			if i%2 == 1 {
+				// the same set of IDs in another order
+				ids = []string{cacheConceptB, cacheConceptA}
+			}
+			concepts, err := c.GetConceptsByIDs(context.Background(), ids)
+			assert.NoError(t, err)
+			results[i] = concepts
+		}(i)
+	}
+
+	for atomic.LoadInt32(&api.reads) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	// let the other reads join the one in flight
+	time.Sleep(20 * time.Millisecond)
+	close(api.release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&api.reads))
+	// each caller can change its concepts without affecting the others
+	delete(results[0], cacheConceptA)
+	for _, concepts := range results[1:] {
+		assert.Len(t, concepts, 2)
+	}
+}
+
+func TestCoalescingReadAPIPartialResults(t *testing.T) {
+	api := &partialReadAPI{fakeReadAPI: *newFakeReadAPI(), failedID: cacheConceptB}
+	c := NewCoalescingReadAPI(api, time.Second)
+
+	concepts, err := c.GetConceptsByIDs(context.Background(), []string{cacheConceptA, cacheConceptB})
+	var partialErr *PartialResultsError
+	assert.ErrorAs(t, err, &partialErr)
+	assert.Equal(t, map[string]Concept{cacheConceptA: api.concepts[cacheConceptA]}, concepts)
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 
 	tidUtils "github.com/Financial-Times/transactionid-utils-go"
 	log "github.com/sirupsen/logrus"
@@ -19,25 +20,93 @@ type ReadAPI interface {
 }
 
 type internalConcordancesAPI struct {
-	endpoint   string
-	username   string
-	password   string
-	httpClient *http.Client
-	batchSize  int
+	endpoint       string
+	username       string
+	password       string
+	httpClient     *http.Client
+	batchSize      int
+	parallelism    int
+	partialResults bool
 }
 
-func NewReadAPI(client *http.Client, endpoint string, username string, password string, batchSize int) ReadAPI {
-	return &internalConcordancesAPI{
-		endpoint:   endpoint,
-		username:   username,
-		password:   password,
-		httpClient: client,
-		batchSize:  batchSize,
+// ReadOption configures the ReadAPI returned by NewReadAPI.
+type ReadOption func(*internalConcordancesAPI)
+
+// WithParallelism sets the maximum number of concept batches fetched concurrently, one by default.
+func WithParallelism(parallelism int) ReadOption {
+	return func(search *internalConcordancesAPI) {
+		if parallelism > 0 {
+			search.parallelism = parallelism
+		}
+	}
+}
+
+// WithPartialResults makes GetConceptsByIDs return the concepts of the batches fetched successfully
+// along with a *PartialResultsError reporting the failed batches, instead of failing on any batch error.
+func WithPartialResults() ReadOption {
+	return func(search *internalConcordancesAPI) {
+		search.partialResults = true
+	}
+}
+
+func NewReadAPI(client *http.Client, endpoint string, username string, password string, batchSize int, opts ...ReadOption) ReadAPI {
+	search := &internalConcordancesAPI{
+		endpoint:    endpoint,
+		username:    username,
+		password:    password,
+		httpClient:  client,
+		batchSize:   batchSize,
+		parallelism: 1,
 	}
+	for _, opt := range opts {
+		opt(search)
+	}
+	return search
 }
 
 var ErrUnexpectedResponse = errors.New("concept search API returned a non-200 HTTP status code")
 
+// BatchError is the failure of the fetch of a batch of concepts.
+type BatchError struct {
+	IDs []string
+	Err error
+}
+
+func (e BatchError) Error() string {
+	return fmt.Sprintf("failed to fetch concepts batch of %d IDs: %v", len(e.IDs), e.Err)
+}
+
+func (e BatchError) Unwrap() error {
+	return e.Err
+}
+
+// PartialResultsError is returned along with the concepts fetched successfully when some batches have failed
+// and the ReadAPI has been created with WithPartialResults.
+type PartialResultsError struct {
+	Failed []BatchError
+}
+
+func (e *PartialResultsError) Error() string {
+	return fmt.Sprintf("%d concepts batches failed, first error: %v", len(e.Failed), e.Failed[0].Err)
+}
+
+func (e *PartialResultsError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failed))
+	for _, f := range e.Failed {
+		errs = append(errs, f)
+	}
+	return errs
+}
+
+// FailedIDs returns the concept IDs of the failed batches.
+func (e *PartialResultsError) FailedIDs() []string {
+	var ids []string
+	for _, f := range e.Failed {
+		ids = append(ids, f.IDs...)
+	}
+	return ids
+}
+
 func (search *internalConcordancesAPI) GetConceptsByIDs(ctx context.Context, conceptIDs []string) (map[string]Concept, error) {
 	tid, err := tidUtils.GetTransactionIDFromContext(ctx)
 	if err != nil {
@@ -48,29 +117,65 @@ func (search *internalConcordancesAPI) GetConceptsByIDs(ctx context.Context, con
 		ctx = tidUtils.TransactionAwareContext(ctx, tid)
 	}
 
-	var conceptIDsBatch []string
-	combinedResult := make(map[string]Concept)
-
-	n := len(conceptIDs)
-	for i := 0; i < n; i++ {
-		conceptIDsBatch = append(conceptIDsBatch, conceptIDs[i])
-		if ((i+1)%search.batchSize == 0) && (i != 0) || (i+1 == n) {
-			conceptsBatch, err := search.searchConceptBatch(ctx, conceptIDsBatch)
+	// the pending batches are cancelled as soon as one fails, unless partial results are returned
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		lock           sync.Mutex
+		wg             sync.WaitGroup
+		failed         []BatchError
+		combinedResult = make(map[string]Concept)
+		semaphore      = make(chan struct{}, search.parallelism)
+	)
+	for i, batch := range search.batches(conceptIDs) {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int, batch []string) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			conceptsBatch, err := search.searchConceptBatch(ctx, batch)
+
+			lock.Lock()
+			defer lock.Unlock()
 			if err != nil {
-				log.WithError(err).WithField(tidUtils.TransactionIDKey, tid).Info("Failed to fetch concepts batch")
-				return nil, err
+				log.WithError(err).WithField(tidUtils.TransactionIDKey, tid).WithField("batch", i).Info("Failed to fetch concepts batch")
+				failed = append(failed, BatchError{IDs: batch, Err: err})
+				if !search.partialResults {
+					cancel()
+				}
+				return
 			}
-
 			for uuid, c := range conceptsBatch {
 				combinedResult[uuid] = c
 			}
-			conceptIDsBatch = []string{}
+		}(i, batch)
+	}
+	wg.Wait()
+
+	if len(failed) > 0 {
+		if !search.partialResults {
+			return nil, failed[0].Err
 		}
+		log.WithField(tidUtils.TransactionIDKey, tid).WithField("failedBatches", len(failed)).Warn("Concepts information partially fetched")
+		return combinedResult, &PartialResultsError{Failed: failed}
 	}
 	log.WithField(tidUtils.TransactionIDKey, tid).Info("Concepts information fetched successfully")
 	return combinedResult, nil
 }
 
+// batches splits the given concept IDs in batches of at most batchSize IDs.
+func (search *internalConcordancesAPI) batches(conceptIDs []string) [][]string {
+	var batches [][]string
+	for len(conceptIDs) > 0 {
+		n := min(search.batchSize, len(conceptIDs))
+		batches = append(batches, conceptIDs[:n])
+		conceptIDs = conceptIDs[n:]
+	}
+	return batches
+}
+
 func (search *internalConcordancesAPI) searchConceptBatch(ctx context.Context, conceptIDs []string) (map[string]Concept, error) {
 	tid, _ := tidUtils.GetTransactionIDFromContext(ctx)
 	batchConceptsLog := log.WithField(tidUtils.TransactionIDKey, tid)
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"sync"
 	"testing"
 	"time"
 
@@ -61,6 +62,76 @@ func TestGetConceptsByIDsMultipleBatches(t *testing.T) {
 	assert.Equal(t, expectedConcepts, actualConcepts)
 }
 
+func TestGetConceptsByIDsParallelBatches(t *testing.T) {
+	batchSize := 2
+	parallelism := 3
+	expectedConcepts := generateConcepts(10)
+
+	var lock sync.Mutex
+	var inFlight, maxInFlight int
+	s := newFailingBatchSearchService(t, expectedConcepts, "", func() {
+		lock.Lock()
+		inFlight++
+		maxInFlight = max(maxInFlight, inFlight)
+		lock.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		lock.Lock()
+		inFlight--
+		lock.Unlock()
+	})
+	defer s.Close()
+
+	csAPI := NewReadAPI(testClient, s.URL, testBasicAuthUsername, testBasicAuthPassword, batchSize, WithParallelism(parallelism))
+
+	ctx := tidUtils.TransactionAwareContext(context.Background(), tidUtils.NewTransactionID())
+	actualConcepts, err := csAPI.GetConceptsByIDs(ctx, extractIDs(expectedConcepts))
+	assert.NoError(t, err)
+	assert.Equal(t, expectedConcepts, actualConcepts)
+	assert.Equal(t, parallelism, maxInFlight)
+}
+
+func TestGetConceptsByIDsFailsOnAnyBatchError(t *testing.T) {
+	expectedConcepts := generateConcepts(10)
+	ids := extractIDs(expectedConcepts)
+
+	s := newFailingBatchSearchService(t, expectedConcepts, ids[4], nil)
+	defer s.Close()
+
+	csAPI := NewReadAPI(testClient, s.URL, testBasicAuthUsername, testBasicAuthPassword, 3, WithParallelism(2))
+
+	ctx := tidUtils.TransactionAwareContext(context.Background(), tidUtils.NewTransactionID())
+	actualConcepts, err := csAPI.GetConceptsByIDs(ctx, ids)
+	assert.True(t, errors.Is(err, ErrUnexpectedResponse))
+	assert.Nil(t, actualConcepts)
+}
+
+func TestGetConceptsByIDsPartialResults(t *testing.T) {
+	expectedConcepts := generateConcepts(10)
+	ids := extractIDs(expectedConcepts)
+
+	s := newFailingBatchSearchService(t, expectedConcepts, ids[4], nil)
+	defer s.Close()
+
+	csAPI := NewReadAPI(testClient, s.URL, testBasicAuthUsername, testBasicAuthPassword, 3, WithParallelism(2), WithPartialResults())
+
+	ctx := tidUtils.TransactionAwareContext(context.Background(), tidUtils.NewTransactionID())
+	actualConcepts, err := csAPI.GetConceptsByIDs(ctx, ids)
+
+	var partialErr *PartialResultsError
+	assert.True(t, errors.As(err, &partialErr))
+	assert.True(t, errors.Is(err, ErrUnexpectedResponse))
+	assert.Len(t, partialErr.Failed, 1)
+	assert.Equal(t, ids[3:6], partialErr.FailedIDs())
+
+	assert.Len(t, actualConcepts, 7)
+	for i, id := range ids {
+		_, found := actualConcepts[id]
+		assert.Equal(t, i < 3 || i >= 6, found)
+	}
+}
+
 func TestGetConceptsByIDsMissingTID(t *testing.T) {
 	hook := logTest.NewGlobal()
 	batchSize := 20
@@ -269,6 +340,27 @@ func (h *mockedSearchServiceHandler) ServeHTTP(w http.ResponseWriter, r *http.Re
 	w.Write(b)
 }
 
+// newFailingBatchSearchService returns the requested concepts, except for the batches holding failingID
+// which are answered with a 503 status. The onRequest function, if any, is called for every request.
+func newFailingBatchSearchService(t *testing.T, concepts map[string]Concept, failingID string, onRequest func()) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if onRequest != nil {
+			onRequest()
+		}
+		result := make(map[string]Concept)
+		for _, id := range r.URL.Query()["ids"] {
+			if id == failingID {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			result[id] = concepts[id]
+		}
+		b, err := json.Marshal(SearchResult{result})
+		assert.NoError(t, err)
+		_, _ = w.Write(b)
+	}))
+}
+
 func newMockedUnhappySearchService(status int, msg string) *httptest.Server {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(status)
@@ -0,0 +1,108 @@
+package concept
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/Financial-Times/draft-annotations-api/mapper"
+	tidUtils "github.com/Financial-Times/transactionid-utils-go"
+	log "github.com/sirupsen/logrus"
+)
+
+// SearchAPI searches concepts by their labels, e.g. for a typeahead.
+type SearchAPI interface {
+	SearchConcepts(ctx context.Context, query string, types []string) ([]Concept, error)
+	Endpoint() string
+}
+
+// searchResponse models the data returned from the UPP concept search API
+type searchResponse struct {
+	Concepts []Concept `json:"concepts"`
+}
+
+type conceptSearchAPI struct {
+	endpoint   string
+	username   string
+	password   string
+	httpClient *http.Client
+}
+
+// NewSearchAPI initializes a SearchAPI calling the UPP concept search API at the given endpoint.
+func NewSearchAPI(client *http.Client, endpoint string, username string, password string) SearchAPI {
+	return &conceptSearchAPI{
+		endpoint:   endpoint,
+		username:   username,
+		password:   password,
+		httpClient: client,
+	}
+}
+
+// SearchConcepts returns the concepts of the given types matching the given query.
+// The IDs of the returned concepts are in the http://www.ft.com/thing/ form used by the annotations.
+func (search *conceptSearchAPI) SearchConcepts(ctx context.Context, query string, types []string) ([]Concept, error) {
+	tid, err := tidUtils.GetTransactionIDFromContext(ctx)
+	if err != nil {
+		tid = tidUtils.NewTransactionID()
+		log.WithField(tidUtils.TransactionIDKey, tid).
+			WithError(err).
+			Info("No Transaction ID provided for concept search request, so a new one has been generated.")
+		ctx = tidUtils.TransactionAwareContext(ctx, tid)
+	}
+	searchLog := log.WithField(tidUtils.TransactionIDKey, tid)
+
+	req, err := http.NewRequest("GET", search.endpoint, nil)
+	if err != nil {
+		searchLog.WithError(err).Error("Error in creating the HTTP request to concept search API")
+		return nil, err
+	}
+	req.SetBasicAuth(search.username, search.password)
+	req.Header.Set(tidUtils.TransactionIDHeader, tid)
+	q := req.URL.Query()
+	q.Set("q", query)
+	for _, conceptType := range types {
+		q.Add("type", conceptType)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := search.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		searchLog.WithError(err).Error("Error making the HTTP request to concept search API")
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, e := io.ReadAll(resp.Body)
+		if e != nil {
+			err = fmt.Errorf("status %d: %w", resp.StatusCode, ErrUnexpectedResponse)
+		} else {
+			err = fmt.Errorf("status %d %s: %w", resp.StatusCode, string(body), ErrUnexpectedResponse)
+		}
+		searchLog.WithError(err).Error("Error received from concept search API")
+		return nil, err
+	}
+
+	var result searchResponse
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		searchLog.WithError(err).Error("Error in unmarshalling the HTTP response from concept search API")
+		return nil, err
+	}
+
+	concepts := make([]Concept, 0, len(result.Concepts))
+	for _, c := range result.Concepts {
+		c.ID = mapper.TransformConceptID(c.ID)
+		if c.ID == "" {
+			continue
+		}
+		concepts = append(concepts, c)
+	}
+	return concepts, nil
+}
+
+func (search *conceptSearchAPI) Endpoint() string {
+	return search.endpoint
+}
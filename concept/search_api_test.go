@@ -0,0 +1,82 @@
+package concept
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	tidUtils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testPersonType       = "http://www.ft.com/ontology/person/Person"
+	testOrganisationType = "http://www.ft.com/ontology/organisation/Organisation"
+)
+
+func TestSearchConcepts(t *testing.T) {
+	tid := tidUtils.NewTransactionID()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, testBasicAuthUsername, username)
+		assert.Equal(t, testBasicAuthPassword, password)
+		assert.Equal(t, tid, r.Header.Get(tidUtils.TransactionIDHeader))
+		assert.Equal(t, "obama", r.URL.Query().Get("q"))
+		assert.Equal(t, []string{testPersonType, testOrganisationType}, r.URL.Query()["type"])
+
+		_, _ = w.Write([]byte(`{"concepts":[
+			{
+				"id": "http://api.ft.com/things/0a619d71-9af5-3755-90dd-f789b686c67a",
+				"apiUrl": "http://api.ft.com/people/0a619d71-9af5-3755-90dd-f789b686c67a",
+				"type": "http://www.ft.com/ontology/person/Person",
+				"prefLabel": "Barack H. Obama"
+			},
+			{
+				"id": "",
+				"prefLabel": "Without ID"
+			}
+		]}`))
+	}))
+	defer s.Close()
+
+	searchAPI := NewSearchAPI(testClient, s.URL, testBasicAuthUsername, testBasicAuthPassword)
+	ctx := tidUtils.TransactionAwareContext(context.Background(), tid)
+	concepts, err := searchAPI.SearchConcepts(ctx, "obama", []string{testPersonType, testOrganisationType})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Concept{
+		{
+			ID:        "http://www.ft.com/thing/0a619d71-9af5-3755-90dd-f789b686c67a",
+			ApiUrl:    "http://api.ft.com/people/0a619d71-9af5-3755-90dd-f789b686c67a",
+			Type:      testPersonType,
+			PrefLabel: "Barack H. Obama",
+		},
+	}, concepts)
+}
+
+func TestSearchConceptsNon200HTTPStatus(t *testing.T) {
+	s := newMockedUnhappySearchService(http.StatusServiceUnavailable, "I am not happy")
+	defer s.Close()
+
+	searchAPI := NewSearchAPI(testClient, s.URL, testBasicAuthUsername, testBasicAuthPassword)
+	ctx := tidUtils.TransactionAwareContext(context.Background(), tidUtils.NewTransactionID())
+	_, err := searchAPI.SearchConcepts(ctx, "obama", nil)
+
+	assert.True(t, errors.Is(err, ErrUnexpectedResponse))
+}
+
+func TestSearchConceptsUnmarshallingPayloadError(t *testing.T) {
+	s := newMockedUnhappySearchService(http.StatusOK, "}-a-wrong-json-payload-{")
+	defer s.Close()
+
+	searchAPI := NewSearchAPI(testClient, s.URL, testBasicAuthUsername, testBasicAuthPassword)
+	ctx := tidUtils.TransactionAwareContext(context.Background(), tidUtils.NewTransactionID())
+	_, err := searchAPI.SearchConcepts(ctx, "obama", nil)
+
+	var jsonErr *json.SyntaxError
+	assert.True(t, errors.As(err, &jsonErr))
+}
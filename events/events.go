@@ -0,0 +1,43 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/Financial-Times/draft-annotations-api/annotations"
+)
+
+// Types of the events published when the draft annotations of a content change.
+const (
+	Saved     = "saved"
+	Discarded = "discarded"
+)
+
+// Event notifies a change to the draft annotations of a content.
+// Hash is the Document-Hash of the draft after the change, not set when the draft has been discarded.
+// Changes is the canonical diff between the draft before and after the change,
+// and is not set when it is not known.
+type Event struct {
+	Type          string            `json:"type"`
+	ContentUUID   string            `json:"uuid"`
+	Hash          string            `json:"hash,omitempty"`
+	PreviousHash  string            `json:"previousHash,omitempty"`
+	TransactionID string            `json:"transactionId"`
+	Timestamp     time.Time         `json:"timestamp"`
+	Changes       *annotations.Diff `json:"changes,omitempty"`
+}
+
+// Publisher is notified of the changes to the draft annotations.
+type Publisher interface {
+	// Publish sends the given event to its recipients.
+	Publish(ctx context.Context, event Event) error
+}
+
+// Hub dispatches the events of each content to its subscribers.
+// Publish sends the given event to the current subscribers of its content.
+type Hub interface {
+	Publisher
+	// Subscribe returns a channel receiving the events of the given content, and a function to cancel the subscription,
+	// which closes the channel.
+	Subscribe(contentUUID string) (<-chan Event, func())
+}
@@ -0,0 +1,60 @@
+package events
+
+import (
+	"context"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type memoryHub struct {
+	sync.Mutex
+	subscribers map[string]map[chan Event]struct{}
+	bufferSize  int
+}
+
+// NewMemoryHub returns a Hub dispatching the events to the subscribers in the same process.
+// Each subscriber can lag behind by up to bufferSize events, after which further events are dropped for it.
+func NewMemoryHub(bufferSize int) Hub {
+	return &memoryHub{subscribers: make(map[string]map[chan Event]struct{}), bufferSize: bufferSize}
+}
+
+func (h *memoryHub) Publish(_ context.Context, event Event) error {
+	h.Lock()
+	defer h.Unlock()
+
+	for ch := range h.subscribers[event.ContentUUID] {
+		select {
+		case ch <- event:
+		default:
+			log.WithField("uuid", event.ContentUUID).Warn("Dropping draft annotations event for a slow subscriber")
+		}
+	}
+	return nil
+}
+
+func (h *memoryHub) Subscribe(contentUUID string) (<-chan Event, func()) {
+	ch := make(chan Event, h.bufferSize)
+
+	h.Lock()
+	defer h.Unlock()
+
+	if h.subscribers[contentUUID] == nil {
+		h.subscribers[contentUUID] = make(map[chan Event]struct{})
+	}
+	h.subscribers[contentUUID][ch] = struct{}{}
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.Lock()
+			defer h.Unlock()
+
+			delete(h.subscribers[contentUUID], ch)
+			if len(h.subscribers[contentUUID]) == 0 {
+				delete(h.subscribers, contentUUID)
+			}
+			close(ch)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testContentUUID = "83a201c6-60cd-11e7-91a7-502f7ee26895"
+
+func TestMemoryHub(t *testing.T) {
+	hub := NewMemoryHub(4)
+	ctx := context.Background()
+
+	first, cancelFirst := hub.Subscribe(testContentUUID)
+	defer cancelFirst()
+	second, cancelSecond := hub.Subscribe(testContentUUID)
+	other, cancelOther := hub.Subscribe("another-content")
+	defer cancelOther()
+
+	event := Event{Type: Saved, ContentUUID: testContentUUID, Hash: "hash-1"}
+	err := hub.Publish(ctx, event)
+	assert.NoError(t, err)
+
+	assert.Equal(t, event, <-first)
+	assert.Equal(t, event, <-second)
+	assert.Empty(t, other)
+
+	cancelSecond()
+	_, open := <-second
+	assert.False(t, open)
+
+	err = hub.Publish(ctx, Event{Type: Discarded, ContentUUID: testContentUUID, PreviousHash: "hash-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, Discarded, (<-first).Type)
+}
+
+func TestMemoryHubDropsEventsForSlowSubscribers(t *testing.T) {
+	hub := NewMemoryHub(1)
+	ctx := context.Background()
+
+	ch, cancel := hub.Subscribe(testContentUUID)
+	defer cancel()
+
+	for _, hash := range []string{"hash-1", "hash-2"} {
+		err := hub.Publish(ctx, Event{Type: Saved, ContentUUID: testContentUUID, Hash: hash})
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, "hash-1", (<-ch).Hash)
+	assert.Empty(t, ch)
+}
+
+func TestMemoryHubCancelTwice(t *testing.T) {
+	hub := NewMemoryHub(1)
+
+	_, cancel := hub.Subscribe(testContentUUID)
+	cancel()
+	cancel()
+
+	err := hub.Publish(context.Background(), Event{Type: Saved, ContentUUID: testContentUUID})
+	assert.NoError(t, err)
+}
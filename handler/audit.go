@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Financial-Times/draft-annotations-api/annotations"
+	"github.com/Financial-Times/draft-annotations-api/audit"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/husobee/vestigo"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// EditorHeader identifies the editor making a change to the draft annotations.
+	EditorHeader = "X-Editor"
+	// OriginSystemIDHeader identifies the system making a change, used as editor when no EditorHeader is given.
+	OriginSystemIDHeader = "X-Origin-System-Id"
+)
+
+// AuditResponse is the body of the audit endpoint response.
+type AuditResponse struct {
+	Entries []audit.Entry `json:"entries"`
+}
+
+type auditContextKey struct{}
+
+// auditInfo identifies who is changing the draft annotations and how.
+type auditInfo struct {
+	editor    string
+	operation string
+}
+
+// auditContext returns a copy of the given context holding the editor and the operation of the given request,
+// to be recorded in the audit trail when the draft annotations are saved.
+func auditContext(ctx context.Context, r *http.Request) context.Context {
+	editor := r.Header.Get(EditorHeader)
+	if editor == "" {
+		editor = r.Header.Get(OriginSystemIDHeader)
+	}
+	return context.WithValue(ctx, auditContextKey{}, auditInfo{editor: editor, operation: r.Method})
+}
+
+// AuditTrail returns the audit entries of the changes made to the draft annotations for a given content uuid, oldest first.
+func (h *Handler) AuditTrail(w http.ResponseWriter, r *http.Request) {
+	contentUUID := vestigo.Param(r, "uuid")
+	tID := tidutils.GetTransactionIDFromRequest(r)
+
+	ctx, cancel := context.WithTimeout(tidutils.TransactionAwareContext(r.Context(), tID), h.timeout)
+	defer cancel()
+
+	readLog := readLogEntry(ctx, contentUUID)
+
+	w.Header().Add("Content-Type", "application/json")
+
+	if err := validateUUID(contentUUID); err != nil {
+		writeMessage(w, "Invalid content UUID: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	reader, ok := h.audit.(audit.Reader)
+	if !ok {
+		writeMessage(w, "Draft annotations audit trail is not readable", http.StatusNotImplemented)
+		return
+	}
+
+	entries, err := reader.Entries(ctx, contentUUID)
+	if err != nil {
+		readLog.WithError(err).Error("Failed to read draft annotations audit trail")
+		writeMessage(w, fmt.Sprintf("Failed to read draft annotations audit trail: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(&AuditResponse{Entries: entries})
+	if err != nil {
+		readLog.WithError(err).Error("Failed to encode response")
+		handleReadErrors(err, readLog, w)
+	}
+}
+
+// recordAudit records the change from the given draft to the saved canonical annotations in the audit trail, if enabled.
+// The change is not rolled back when it cannot be recorded, the missing entry is reported in the logs instead.
+func (h *Handler) recordAudit(ctx context.Context, contentUUID string, before previousDraft, saved []annotations.Annotation, newHash string, writeLog *log.Entry) {
+	if h.audit == nil {
+		return
+	}
+
+	info, _ := ctx.Value(auditContextKey{}).(auditInfo)
+	entry := audit.Entry{
+		Timestamp:    time.Now().UTC(),
+		ContentUUID:  contentUUID,
+		Editor:       info.editor,
+		Operation:    info.operation,
+		PreviousHash: before.hash,
+		Hash:         newHash,
+		Changes:      before.changes(saved),
+	}
+	entry.TransactionID, _ = tidutils.GetTransactionIDFromContext(ctx)
+	if before.err != nil {
+		writeLog.WithError(before.err).Warn("Failed to read draft annotations before the change, recording audit entry without changes")
+	}
+
+	if err := h.audit.Record(ctx, entry); err != nil {
+		writeLog.WithError(err).Warn("Failed to record draft annotations change in audit trail")
+	}
+}
@@ -0,0 +1,302 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Financial-Times/draft-annotations-api/annotations"
+	"github.com/Financial-Times/draft-annotations-api/audit"
+	"github.com/Financial-Times/draft-annotations-api/handler"
+	"github.com/Financial-Times/draft-annotations-api/mapper"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/husobee/vestigo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newAuditRouter(rw *RWMock, annAPI *AnnotationsAPIMock, sink audit.Sink) *vestigo.Router {
+	aug := &AugmenterMock{
+		augment: func(ctx context.Context, depletedAnnotations []annotations.Annotation) ([]annotations.Annotation, error) {
+			return depletedAnnotations, nil
+		},
+	}
+	var opts []handler.Option
+	if sink != nil {
+		opts = append(opts, handler.WithAudit(sink))
+	}
+	h := handler.New(rw, annAPI, annotations.NewCanonicalizer(annotations.NewCanonicalAnnotationSorter), aug, time.Second, opts...)
+	r := vestigo.NewRouter()
+	r.Put("/drafts/content/:uuid/annotations", h.WriteAnnotations)
+	r.Delete("/drafts/content/:uuid/annotations", h.DiscardDraftAnnotations)
+	r.Get("/drafts/content/:uuid/annotations/audit", h.AuditTrail)
+	return r
+}
+
+func newAuditWriteRequest(body string) *http.Request {
+	req := httptest.NewRequest("PUT", "/drafts/content/"+patchContentUUID+"/annotations", strings.NewReader(body))
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	req.Header.Set(annotations.PreviousDocumentHashHeader, "old-hash")
+	return req
+}
+
+func readAuditTrail(t *testing.T, r *vestigo.Router) []audit.Entry {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/drafts/content/"+patchContentUUID+"/annotations/audit", nil))
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	actual := handler.AuditResponse{}
+	err := json.NewDecoder(resp.Body).Decode(&actual)
+	assert.NoError(t, err)
+	return actual.Entries
+}
+
+func TestWriteAnnotationsRecordsAuditEntry(t *testing.T) {
+	before := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+		{Predicate: patchMentions, ConceptId: patchConceptB},
+	}}
+	after := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+		{Predicate: patchAbout, ConceptId: patchConceptC},
+	}}
+
+	rw := new(RWMock)
+	rw.On("Read", mock.Anything, patchContentUUID).Return(before, "old-hash", true, nil)
+	rw.On("Write", mock.Anything, patchContentUUID, after, "old-hash").Return("new-hash", nil)
+
+	r := newAuditRouter(rw, new(AnnotationsAPIMock), audit.NewMemorySink())
+
+	req := newAuditWriteRequest(`{"annotations":[
+		{"predicate":"` + patchAbout + `","id":"` + patchConceptA + `"},
+		{"predicate":"` + patchAbout + `","id":"` + patchConceptC + `"}
+	]}`)
+	req.Header.Set(handler.EditorHeader, "jane.doe")
+	req.Header.Set(handler.OriginSystemIDHeader, "methode-web-pub")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+	entries := readAuditTrail(t, r)
+	assert.Len(t, entries, 1)
+	entry := entries[0]
+	assert.Equal(t, testTID, entry.TransactionID)
+	assert.Equal(t, patchContentUUID, entry.ContentUUID)
+	assert.Equal(t, "jane.doe", entry.Editor)
+	assert.Equal(t, "PUT", entry.Operation)
+	assert.Equal(t, "old-hash", entry.PreviousHash)
+	assert.Equal(t, "new-hash", entry.Hash)
+	assert.Equal(t, &annotations.Diff{
+		Added:            []annotations.Annotation{{Predicate: patchAbout, ConceptId: patchConceptC}},
+		Removed:          []annotations.Annotation{{Predicate: patchMentions, ConceptId: patchConceptB}},
+		PredicateChanged: []annotations.PredicateChange{},
+	}, entry.Changes)
+
+	rw.AssertExpectations(t)
+}
+
+func TestWriteAnnotationsAuditFallsBackToPublishedAnnotations(t *testing.T) {
+	after := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+	}}
+
+	rw := new(RWMock)
+	rw.On("Read", mock.Anything, patchContentUUID).Return(nil, "", false, nil)
+	rw.On("Write", mock.Anything, patchContentUUID, after, "old-hash").Return("new-hash", nil)
+	annAPI := new(AnnotationsAPIMock)
+	annAPI.On("GetAllButV2", mock.Anything, patchContentUUID).Return([]annotations.Annotation{
+		{Predicate: patchMentions, ConceptId: patchConceptA},
+	}, nil)
+
+	r := newAuditRouter(rw, annAPI, audit.NewMemorySink())
+
+	req := newAuditWriteRequest(`{"annotations":[{"predicate":"` + patchAbout + `","id":"` + patchConceptA + `"}]}`)
+	req.Header.Set(handler.OriginSystemIDHeader, "methode-web-pub")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+	entries := readAuditTrail(t, r)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "methode-web-pub", entries[0].Editor)
+	assert.Equal(t, []annotations.PredicateChange{
+		{ConceptId: patchConceptA, From: []string{patchMentions}, To: []string{patchAbout}},
+	}, entries[0].Changes.PredicateChanged)
+
+	rw.AssertExpectations(t)
+	annAPI.AssertExpectations(t)
+}
+
+func TestWriteAnnotationsAuditWithoutPreviousAnnotations(t *testing.T) {
+	after := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+	}}
+
+	rw := new(RWMock)
+	rw.On("Read", mock.Anything, patchContentUUID).Return(nil, "", false, errors.New("sorry something failed"))
+	rw.On("Write", mock.Anything, patchContentUUID, after, "old-hash").Return("new-hash", nil)
+
+	r := newAuditRouter(rw, new(AnnotationsAPIMock), audit.NewMemorySink())
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newAuditWriteRequest(`{"annotations":[{"predicate":"`+patchAbout+`","id":"`+patchConceptA+`"}]}`))
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+	entries := readAuditTrail(t, r)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "new-hash", entries[0].Hash)
+	assert.Nil(t, entries[0].Changes)
+
+	rw.AssertExpectations(t)
+}
+
+func TestWriteAnnotationsAuditComparesPublishedPredicates(t *testing.T) {
+	after := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: mapper.PredicateHasBrand, ConceptId: patchConceptA},
+	}}
+
+	rw := new(RWMock)
+	rw.On("Read", mock.Anything, patchContentUUID).Return(nil, "", false, nil)
+	rw.On("Write", mock.Anything, patchContentUUID, after, "old-hash").Return("new-hash", nil)
+	annAPI := new(AnnotationsAPIMock)
+	// published brands are read with the isClassifiedBy predicate, but saved with hasBrand
+	annAPI.On("GetAllButV2", mock.Anything, patchContentUUID).Return([]annotations.Annotation{
+		{Predicate: mapper.PredicateIsClassifiedBy, ConceptId: patchConceptA, Type: mapper.ConceptTypeBrand},
+	}, nil)
+
+	r := newAuditRouter(rw, annAPI, audit.NewMemorySink())
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newAuditWriteRequest(`{"annotations":[
+		{"predicate":"`+mapper.PredicateIsClassifiedBy+`","id":"`+patchConceptA+`","type":"`+mapper.ConceptTypeBrand+`"}
+	]}`))
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+	entries := readAuditTrail(t, r)
+	assert.Len(t, entries, 1)
+	assert.Empty(t, entries[0].Changes.Added)
+	assert.Empty(t, entries[0].Changes.Removed)
+	assert.Empty(t, entries[0].Changes.PredicateChanged)
+
+	rw.AssertExpectations(t)
+	annAPI.AssertExpectations(t)
+}
+
+func TestWriteAnnotationsAuditAfterMerge(t *testing.T) {
+	store := newMergeHistory(t)
+	ours := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+		{Predicate: patchAbout, ConceptId: patchConceptC},
+		{Predicate: patchMentions, ConceptId: patchConceptB},
+	}}
+	theirs := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+	}}
+	merged := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+		{Predicate: patchAbout, ConceptId: patchConceptC},
+	}}
+
+	// the draft before the write is taken from the history, and then from the draft merged with
+	rw := new(RWMock)
+	rw.On("Write", mock.Anything, patchContentUUID, ours, "base-hash").Return("", errTestConflict).Once()
+	rw.On("Read", mock.Anything, patchContentUUID).Return(theirs, "their-hash", true, nil).Once()
+	rw.On("Write", mock.Anything, patchContentUUID, merged, "their-hash").Return("merged-hash", nil).Once()
+
+	aug := &AugmenterMock{
+		augment: func(ctx context.Context, depletedAnnotations []annotations.Annotation) ([]annotations.Annotation, error) {
+			return depletedAnnotations, nil
+		},
+	}
+	h := handler.New(rw, new(AnnotationsAPIMock), annotations.NewCanonicalizer(annotations.NewCanonicalAnnotationSorter), aug, time.Second,
+		handler.WithHistory(store), handler.WithAudit(audit.NewMemorySink()))
+	r := vestigo.NewRouter()
+	r.Put("/drafts/content/:uuid/annotations", h.WriteAnnotations)
+	r.Get("/drafts/content/:uuid/annotations/audit", h.AuditTrail)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newMergeRequest(`{"annotations":[
+		{"predicate":"`+patchAbout+`","id":"`+patchConceptA+`"},
+		{"predicate":"`+patchMentions+`","id":"`+patchConceptB+`"},
+		{"predicate":"`+patchAbout+`","id":"`+patchConceptC+`"}
+	]}`))
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+	entries := readAuditTrail(t, r)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "their-hash", entries[0].PreviousHash)
+	assert.Equal(t, "merged-hash", entries[0].Hash)
+	assert.Equal(t, []annotations.Annotation{{Predicate: patchAbout, ConceptId: patchConceptC}}, entries[0].Changes.Added)
+	assert.Empty(t, entries[0].Changes.Removed)
+
+	rw.AssertExpectations(t)
+}
+
+func TestDiscardDraftAnnotationsRecordsAuditEntry(t *testing.T) {
+	draft := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+		{Predicate: patchMentions, ConceptId: patchConceptB},
+	}}
+
+	rw := new(RWMock)
+	rw.On("Read", mock.Anything, patchContentUUID).Return(draft, "old-hash", true, nil)
+	rw.On("Delete", mock.Anything, patchContentUUID, "old-hash").Return(nil)
+
+	r := newAuditRouter(rw, new(AnnotationsAPIMock), audit.NewMemorySink())
+
+	req := httptest.NewRequest("DELETE", "/drafts/content/"+patchContentUUID+"/annotations", nil)
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	req.Header.Set(annotations.PreviousDocumentHashHeader, "old-hash")
+	req.Header.Set(handler.EditorHeader, "jane.doe")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Result().StatusCode)
+
+	entries := readAuditTrail(t, r)
+	assert.Len(t, entries, 1)
+	entry := entries[0]
+	assert.Equal(t, testTID, entry.TransactionID)
+	assert.Equal(t, "jane.doe", entry.Editor)
+	assert.Equal(t, "DELETE", entry.Operation)
+	assert.Equal(t, "old-hash", entry.PreviousHash)
+	assert.Empty(t, entry.Hash)
+	assert.Equal(t, &annotations.Diff{
+		Added:            []annotations.Annotation{},
+		Removed:          draft.Annotations,
+		PredicateChanged: []annotations.PredicateChange{},
+	}, entry.Changes)
+
+	rw.AssertExpectations(t)
+}
+
+func TestAuditTrailNotReadable(t *testing.T) {
+	tests := map[string]audit.Sink{
+		"audit disabled": nil,
+		"log sink":       audit.NewLogSink(),
+	}
+
+	for name, sink := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := newAuditRouter(new(RWMock), new(AnnotationsAPIMock), sink)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest("GET", "/drafts/content/"+patchContentUUID+"/annotations/audit", nil))
+			assert.Equal(t, http.StatusNotImplemented, w.Result().StatusCode)
+		})
+	}
+}
+
+func TestAuditTrailInvalidUUID(t *testing.T) {
+	r := newAuditRouter(new(RWMock), new(AnnotationsAPIMock), audit.NewMemorySink())
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/drafts/content/foo/annotations/audit", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+}
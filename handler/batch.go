@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/Financial-Times/draft-annotations-api/annotations"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// maxBatchReadSize is the maximum number of content UUIDs accepted by a single batch read request.
+	maxBatchReadSize = 100
+	// batchReadConcurrency is the maximum number of content items read concurrently in a batch read request.
+	batchReadConcurrency = 10
+)
+
+// BatchReadRequest is the body of a batch read request.
+type BatchReadRequest struct {
+	UUIDs []string `json:"uuids"`
+}
+
+// BatchReadResponse is the body of a batch read response.
+type BatchReadResponse struct {
+	Results []BatchReadResult `json:"results"`
+}
+
+// BatchReadResult holds the annotations of a single content item in a batch read response,
+// or the error that prevented them from being read or augmented.
+// Unresolved lists the concepts of the annotations whose concept data has not been found.
+type BatchReadResult struct {
+	UUID        string                          `json:"uuid"`
+	Hash        string                          `json:"hash,omitempty"`
+	Annotations []annotations.Annotation        `json:"annotations,omitempty"`
+	Unresolved  []annotations.UnresolvedConcept `json:"unresolved,omitempty"`
+	Error       *BatchReadError                 `json:"error,omitempty"`
+}
+
+// BatchReadError describes why the annotations of a content item in a batch could not be read.
+type BatchReadError struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+type batchFetchResult struct {
+	annotations []annotations.Annotation
+	hash        string
+	err         *BatchReadError
+}
+
+// BatchReadAnnotations gets the annotations for a list of content uuids.
+// For each content, draft annotations are returned if there are any, otherwise the published annotations are returned.
+// The annotations of all the content items are augmented with a single call to the concepts API;
+// if it fails, the error is returned for each content item which has been read, unless the degraded reads are enabled.
+func (h *Handler) BatchReadAnnotations(w http.ResponseWriter, r *http.Request) {
+	tID := tidutils.GetTransactionIDFromRequest(r)
+
+	ctx, cancel := context.WithTimeout(tidutils.TransactionAwareContext(r.Context(), tID), h.timeout)
+	defer cancel()
+
+	readLog := log.WithField(tidutils.TransactionIDKey, tID)
+
+	w.Header().Add("Content-Type", "application/json")
+
+	showHasBrand, err := sendHasBrandParam(r)
+	if err != nil {
+		writeMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var batchReq BatchReadRequest
+	err = json.NewDecoder(r.Body).Decode(&batchReq)
+	if err != nil {
+		writeMessage(w, fmt.Sprintf("Unable to unmarshal batch read body: %v", err), http.StatusBadRequest)
+		return
+	}
+	if len(batchReq.UUIDs) == 0 {
+		writeMessage(w, "No content UUIDs provided", http.StatusBadRequest)
+		return
+	}
+	if len(batchReq.UUIDs) > maxBatchReadSize {
+		writeMessage(w, fmt.Sprintf("Too many content UUIDs provided, the maximum is %d", maxBatchReadSize), http.StatusBadRequest)
+		return
+	}
+
+	uuids := dedupeUUIDs(batchReq.UUIDs)
+	fetched := h.fetchAnnotationsBatch(ctx, uuids)
+
+	toAugment := make(map[string][]annotations.Annotation)
+	for contentUUID, res := range fetched {
+		if res.err == nil {
+			toAugment[contentUUID] = res.annotations
+		}
+	}
+
+	augmented, aug, err := h.augmentBatchForRead(ctx, toAugment, readLog)
+	var augmentErr *BatchReadError
+	if err != nil {
+		status, msg := readErrorStatus(err)
+		augmentErr = &BatchReadError{Status: status, Message: msg}
+	}
+	if aug.degraded {
+		w.Header().Set(PartiallyAugmentedHeader, "true")
+	}
+
+	response := BatchReadResponse{Results: make([]BatchReadResult, 0, len(uuids))}
+	for _, contentUUID := range uuids {
+		res := fetched[contentUUID]
+		result := BatchReadResult{UUID: contentUUID, Hash: res.hash, Error: res.err}
+		if res.err == nil && augmentErr != nil {
+			result.Error = augmentErr
+		} else if res.err == nil {
+			result.Annotations = augmented[contentUUID]
+			result.Unresolved = aug.unresolved[contentUUID]
+			if !showHasBrand {
+				result.Annotations = switchToIsClassifiedBy(result.Annotations)
+			}
+			result.Annotations = canonicalOrder(result.Annotations)
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	err = json.NewEncoder(w).Encode(&response)
+	if err != nil {
+		readLog.WithError(err).Error("Failed to encode batch response")
+	}
+}
+
+// batchAugmentation reports how the annotations of a batch have been augmented.
+type batchAugmentation struct {
+	unresolved map[string][]annotations.UnresolvedConcept
+	degraded   bool
+}
+
+// augmentBatchForRead augments the given lists of annotations with recent UPP data as augmentForRead does for a single list:
+// with the degraded reads, they are partially augmented instead of failing when the concept data cannot be read.
+func (h *Handler) augmentBatchForRead(ctx context.Context, batch map[string][]annotations.Annotation, readLog *log.Entry) (map[string][]annotations.Annotation, batchAugmentation, error) {
+	readLog.WithField("count", len(batch)).Info("Augmenting annotations batch with recent UPP data")
+	var aug batchAugmentation
+	if h.degradedReads {
+		var augmented map[string][]annotations.Annotation
+		augmented, aug.unresolved, aug.degraded = h.annotationsAugmenter.AugmentAnnotationsBatchOrDegrade(ctx, batch)
+		if aug.degraded {
+			readLog.Warn("Annotations batch has been partially augmented")
+		}
+		return augmented, aug, nil
+	}
+
+	augmented, unresolved, err := h.annotationsAugmenter.AugmentAnnotationsBatch(ctx, batch)
+	if err != nil {
+		readLog.WithError(err).Error("Failed to augment annotations batch")
+		return nil, batchAugmentation{}, err
+	}
+	aug.unresolved = unresolved
+	return augmented, aug, nil
+}
+
+// fetchAnnotationsBatch reads the not augmented annotations of the given content items
+// using at most batchReadConcurrency concurrent reads.
+func (h *Handler) fetchAnnotationsBatch(ctx context.Context, uuids []string) map[string]batchFetchResult {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[string]batchFetchResult, len(uuids))
+		sem     = make(chan struct{}, batchReadConcurrency)
+	)
+
+	for _, contentUUID := range uuids {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(contentUUID string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			res := h.fetchBatchItem(ctx, contentUUID)
+
+			mu.Lock()
+			results[contentUUID] = res
+			mu.Unlock()
+		}(contentUUID)
+	}
+	wg.Wait()
+
+	return results
+}
+
+func (h *Handler) fetchBatchItem(ctx context.Context, contentUUID string) batchFetchResult {
+	if err := validateUUID(contentUUID); err != nil {
+		return batchFetchResult{err: &BatchReadError{Status: http.StatusBadRequest, Message: fmt.Sprintf("Invalid content UUID: %v", err)}}
+	}
+
+	readLog := readLogEntry(ctx, contentUUID)
+	result, hash, err := h.fetchAnnotations(ctx, h.readRW, contentUUID, readLog)
+	if err != nil {
+		readLog.WithError(err).Error("Failed to read annotations in batch")
+		status, msg := readErrorStatus(err)
+		return batchFetchResult{err: &BatchReadError{Status: status, Message: msg}}
+	}
+	return batchFetchResult{annotations: result, hash: hash}
+}
+
+func dedupeUUIDs(uuids []string) []string {
+	seen := make(map[string]struct{}, len(uuids))
+	deduped := make([]string, 0, len(uuids))
+	for _, u := range uuids {
+		if _, found := seen[u]; found {
+			continue
+		}
+		seen[u] = struct{}{}
+		deduped = append(deduped, u)
+	}
+	return deduped
+}
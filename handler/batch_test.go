@@ -0,0 +1,217 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Financial-Times/draft-annotations-api/annotations"
+	"github.com/Financial-Times/draft-annotations-api/handler"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/husobee/vestigo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	batchDraftUUID     = "83a201c6-60cd-11e7-91a7-502f7ee26895"
+	batchPublishedUUID = "0a619d71-9af5-3755-90dd-f789b686c67a"
+	batchMissingUUID   = "9577c6d4-b09e-4552-b88f-e52745abe02b"
+)
+
+func TestBatchReadAnnotations(t *testing.T) {
+	rw := new(RWMock)
+	rw.On("Read", mock.Anything, batchDraftUUID).Return(&expectedAnnotations, "draft-hash", true, nil)
+	rw.On("Read", mock.Anything, batchPublishedUUID).Return(nil, "", false, nil)
+	rw.On("Read", mock.Anything, batchMissingUUID).Return(nil, "", false, nil)
+
+	published := []annotations.Annotation{expectedAnnotations.Annotations[0]}
+	annAPI := new(AnnotationsAPIMock)
+	annAPI.On("GetAll", mock.Anything, batchPublishedUUID).Return(published, nil)
+	annAPI.On("GetAll", mock.Anything, batchMissingUUID).Return([]annotations.Annotation{}, annotations.NewUPPError(annotations.UPPNotFoundMsg, http.StatusNotFound, nil))
+
+	aug := new(AugmenterMock)
+	aug.On("AugmentAnnotationsBatch", mock.Anything, map[string][]annotations.Annotation{
+		batchDraftUUID:     expectedAnnotations.Annotations,
+		batchPublishedUUID: published,
+	}).Return(map[string][]annotations.Annotation{
+		batchDraftUUID:     expectedAnnotations.Annotations,
+		batchPublishedUUID: published,
+	}, nil).Once()
+
+	h := handler.New(rw, annAPI, nil, aug, time.Second)
+	r := vestigo.NewRouter()
+	r.Post("/drafts/content/annotations/batch", h.BatchReadAnnotations)
+
+	body := `{"uuids":["` + batchDraftUUID + `","` + batchPublishedUUID + `","` + batchMissingUUID + `","not-a-uuid","` + batchDraftUUID + `"]}`
+	req := httptest.NewRequest("POST", "/drafts/content/annotations/batch", strings.NewReader(body))
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var actual handler.BatchReadResponse
+	err := json.NewDecoder(resp.Body).Decode(&actual)
+	assert.NoError(t, err)
+
+	assert.Len(t, actual.Results, 4)
+
+	assert.Equal(t, batchDraftUUID, actual.Results[0].UUID)
+	assert.Equal(t, "draft-hash", actual.Results[0].Hash)
+	assert.Equal(t, canonicallySorted(expectedAnnotations).Annotations, actual.Results[0].Annotations)
+	assert.Nil(t, actual.Results[0].Error)
+
+	assert.Equal(t, batchPublishedUUID, actual.Results[1].UUID)
+	assert.Empty(t, actual.Results[1].Hash)
+	assert.Equal(t, published, actual.Results[1].Annotations)
+	assert.Nil(t, actual.Results[1].Error)
+
+	assert.Equal(t, batchMissingUUID, actual.Results[2].UUID)
+	assert.Empty(t, actual.Results[2].Annotations)
+	assert.Equal(t, &handler.BatchReadError{Status: http.StatusNotFound, Message: annotations.UPPNotFoundMsg}, actual.Results[2].Error)
+
+	assert.Equal(t, "not-a-uuid", actual.Results[3].UUID)
+	assert.Equal(t, http.StatusBadRequest, actual.Results[3].Error.Status)
+
+	rw.AssertExpectations(t)
+	annAPI.AssertExpectations(t)
+	aug.AssertExpectations(t)
+}
+
+func TestBatchReadAnnotationsInvalidBody(t *testing.T) {
+	tests := map[string]string{
+		"malformed body": `{"uuids":`,
+		"no uuids":       `{"uuids":[]}`,
+		"too many uuids": `{"uuids":[` + strings.Repeat(`"`+batchDraftUUID+`",`, 100) + `"` + batchDraftUUID + `"]}`,
+	}
+
+	h := handler.New(new(RWMock), new(AnnotationsAPIMock), nil, new(AugmenterMock), time.Second)
+	r := vestigo.NewRouter()
+	r.Post("/drafts/content/annotations/batch", h.BatchReadAnnotations)
+
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/drafts/content/annotations/batch", strings.NewReader(body))
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+			resp := w.Result()
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+	}
+}
+
+func TestBatchReadAnnotationsAugmenterError(t *testing.T) {
+	rw := new(RWMock)
+	rw.On("Read", mock.Anything, batchDraftUUID).Return(&expectedAnnotations, "draft-hash", true, nil)
+
+	aug := &AugmenterMock{
+		augmentBatch: func(ctx context.Context, depletedAnnotations map[string][]annotations.Annotation) (map[string][]annotations.Annotation, error) {
+			return nil, errors.New("computer says no")
+		},
+	}
+
+	h := handler.New(rw, new(AnnotationsAPIMock), nil, aug, time.Second)
+	r := vestigo.NewRouter()
+	r.Post("/drafts/content/annotations/batch", h.BatchReadAnnotations)
+
+	req := httptest.NewRequest("POST", "/drafts/content/annotations/batch", strings.NewReader(`{"uuids":["`+batchDraftUUID+`","not-a-uuid"]}`))
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var actual handler.BatchReadResponse
+	err := json.NewDecoder(resp.Body).Decode(&actual)
+	assert.NoError(t, err)
+
+	assert.Len(t, actual.Results, 2)
+	assert.Equal(t, batchDraftUUID, actual.Results[0].UUID)
+	assert.Empty(t, actual.Results[0].Annotations)
+	assert.Equal(t, &handler.BatchReadError{Status: http.StatusInternalServerError, Message: "Failed to read annotations: computer says no"}, actual.Results[0].Error)
+	assert.Equal(t, "not-a-uuid", actual.Results[1].UUID)
+	assert.Equal(t, http.StatusBadRequest, actual.Results[1].Error.Status)
+
+	rw.AssertExpectations(t)
+}
+
+func TestBatchReadAnnotationsDegraded(t *testing.T) {
+	rw := new(RWMock)
+	rw.On("Read", mock.Anything, batchDraftUUID).Return(&expectedAnnotations, "draft-hash", true, nil)
+
+	aug := &AugmenterMock{
+		augmentBatch: func(_ context.Context, _ map[string][]annotations.Annotation) (map[string][]annotations.Annotation, error) {
+			return nil, errors.New("computer says no")
+		},
+		augmentBatchOrDegrade: func(_ context.Context, batch map[string][]annotations.Annotation) (map[string][]annotations.Annotation, bool) {
+			return batch, true
+		},
+	}
+
+	h := handler.New(rw, new(AnnotationsAPIMock), nil, aug, time.Second, handler.WithDegradedReads())
+	r := vestigo.NewRouter()
+	r.Post("/drafts/content/annotations/batch", h.BatchReadAnnotations)
+
+	req := httptest.NewRequest("POST", "/drafts/content/annotations/batch", strings.NewReader(`{"uuids":["`+batchDraftUUID+`"]}`))
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "true", resp.Header.Get(handler.PartiallyAugmentedHeader))
+
+	var actual handler.BatchReadResponse
+	err := json.NewDecoder(resp.Body).Decode(&actual)
+	assert.NoError(t, err)
+
+	assert.Len(t, actual.Results, 1)
+	assert.Nil(t, actual.Results[0].Error)
+	assert.Equal(t, canonicallySorted(expectedAnnotations).Annotations, actual.Results[0].Annotations)
+
+	rw.AssertExpectations(t)
+}
+
+func TestBatchReadAnnotationsWithUnresolvedConcepts(t *testing.T) {
+	rw := new(RWMock)
+	rw.On("Read", mock.Anything, batchDraftUUID).Return(&annotations.Annotations{Annotations: unresolvedTestAnnotations()}, "draft-hash", true, nil)
+
+	aug := &AugmenterMock{
+		augmentBatch: func(_ context.Context, _ map[string][]annotations.Annotation) (map[string][]annotations.Annotation, error) {
+			return map[string][]annotations.Annotation{batchDraftUUID: resolvedAnnotations()}, nil
+		},
+		unresolvedBatch: map[string][]annotations.UnresolvedConcept{batchDraftUUID: testUnresolved},
+	}
+
+	h := handler.New(rw, new(AnnotationsAPIMock), nil, aug, time.Second)
+	r := vestigo.NewRouter()
+	r.Post("/drafts/content/annotations/batch", h.BatchReadAnnotations)
+
+	req := httptest.NewRequest("POST", "/drafts/content/annotations/batch", strings.NewReader(`{"uuids":["`+batchDraftUUID+`"]}`))
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var actual handler.BatchReadResponse
+	err := json.NewDecoder(resp.Body).Decode(&actual)
+	assert.NoError(t, err)
+
+	assert.Len(t, actual.Results, 1)
+	assert.Equal(t, resolvedAnnotations(), actual.Results[0].Annotations)
+	assert.Equal(t, testUnresolved, actual.Results[0].Unresolved)
+	assert.Nil(t, actual.Results[0].Error)
+}
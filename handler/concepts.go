@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Financial-Times/draft-annotations-api/concept"
+	"github.com/Financial-Times/draft-annotations-api/mapper"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	log "github.com/sirupsen/logrus"
+)
+
+// ConceptSearchAPI searches the concepts that can be annotated.
+type ConceptSearchAPI interface {
+	SearchConcepts(ctx context.Context, query string, types []string) ([]concept.Concept, error)
+}
+
+// ConceptsResponse is the body of the concept search endpoint response.
+type ConceptsResponse struct {
+	Concepts []concept.Concept `json:"concepts"`
+}
+
+// ConceptLookupResponse is the body of the concept lookup endpoint response.
+// Unresolved holds the requested concept IDs which have not been found.
+// Failed holds the requested concept IDs which could not be read, when partial results are enabled.
+type ConceptLookupResponse struct {
+	Concepts   []concept.Concept `json:"concepts"`
+	Unresolved []string          `json:"unresolved"`
+	Failed     []string          `json:"failed,omitempty"`
+}
+
+// ReadConcepts returns the concepts with the IDs given in the ids query parameter, which can be repeated or hold
+// comma-separated IDs, either UUIDs or concept URIs. The concepts are read like the ones of the augmented annotations,
+// and the requested IDs which cannot be resolved are listed as unresolved.
+func (h *Handler) ReadConcepts(w http.ResponseWriter, r *http.Request) {
+	tID := tidutils.GetTransactionIDFromRequest(r)
+
+	ctx, cancel := context.WithTimeout(tidutils.TransactionAwareContext(r.Context(), tID), h.timeout)
+	defer cancel()
+
+	readLog := log.WithField(tidutils.TransactionIDKey, tID)
+
+	w.Header().Add("Content-Type", "application/json")
+
+	if h.conceptRead == nil {
+		writeMessage(w, "Concept lookup is not enabled", http.StatusNotImplemented)
+		return
+	}
+
+	ids, uuids, err := conceptIDsParam(r)
+	if err != nil {
+		writeMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	readLog.WithField("ids", len(ids)).Info("Reading concepts")
+	concepts, err := h.conceptRead.GetConceptsByIDs(ctx, uuids)
+	var partialErr *concept.PartialResultsError
+	if err != nil && !errors.As(err, &partialErr) {
+		readLog.WithError(err).Error("Failed to read concepts")
+		handleReadErrors(err, readLog, w)
+		return
+	}
+
+	failed := make(map[string]struct{})
+	if partialErr != nil {
+		readLog.WithError(err).Warn("Failed to read some of the concepts")
+		for _, uuid := range partialErr.FailedIDs() {
+			failed[uuid] = struct{}{}
+		}
+	}
+
+	response := ConceptLookupResponse{Concepts: make([]concept.Concept, 0, len(ids)), Unresolved: make([]string, 0)}
+	for i, id := range ids {
+		if _, ok := failed[uuids[i]]; ok {
+			response.Failed = append(response.Failed, id)
+			continue
+		}
+		c, found := concepts[uuids[i]]
+		if !found {
+			response.Unresolved = append(response.Unresolved, id)
+			continue
+		}
+		response.Concepts = append(response.Concepts, c)
+	}
+
+	err = json.NewEncoder(w).Encode(&response)
+	if err != nil {
+		readLog.WithError(err).Error("Failed to encode response")
+		handleReadErrors(err, readLog, w)
+	}
+}
+
+// conceptIDsParam returns the distinct concept IDs of the ids query parameter, as they have been requested
+// and as UUIDs in the same order.
+func conceptIDsParam(r *http.Request) ([]string, []string, error) {
+	var ids, uuids []string
+	seen := make(map[string]struct{})
+	for _, param := range r.URL.Query()["ids"] {
+		for _, id := range strings.Split(param, ",") {
+			id = strings.TrimSpace(id)
+			if id == "" {
+				continue
+			}
+			conceptUUID := id
+			if i := strings.LastIndex(id, "/"); i != -1 {
+				conceptUUID = id[i+1:]
+			}
+			if err := validateUUID(conceptUUID); err != nil {
+				return nil, nil, fmt.Errorf("invalid concept ID %s: %w", id, err)
+			}
+			if _, found := seen[conceptUUID]; found {
+				continue
+			}
+			seen[conceptUUID] = struct{}{}
+			ids = append(ids, id)
+			uuids = append(uuids, conceptUUID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil, errors.New("the ids param is required")
+	}
+	return ids, uuids, nil
+}
+
+// SearchConcepts returns the concepts matching the q query parameter which can be annotated in PAC,
+// so that editors can pick the concept of a new annotation. The results can be restricted to the concept types
+// that can be annotated with the predicate query parameter, and to the given type query parameters.
+func (h *Handler) SearchConcepts(w http.ResponseWriter, r *http.Request) {
+	tID := tidutils.GetTransactionIDFromRequest(r)
+
+	ctx, cancel := context.WithTimeout(tidutils.TransactionAwareContext(r.Context(), tID), h.timeout)
+	defer cancel()
+
+	searchLog := log.WithField(tidutils.TransactionIDKey, tID)
+
+	w.Header().Add("Content-Type", "application/json")
+
+	if h.conceptSearch == nil {
+		writeMessage(w, "Concept search is not enabled", http.StatusNotImplemented)
+		return
+	}
+
+	params := r.URL.Query()
+	query := strings.TrimSpace(params.Get("q"))
+	if query == "" {
+		writeMessage(w, "the q param is required", http.StatusBadRequest)
+		return
+	}
+
+	types, err := searchConceptTypes(params.Get("predicate"), params["type"])
+	if err != nil {
+		writeMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	searchLog.WithField("q", query).Info("Searching concepts")
+	concepts, err := h.conceptSearch.SearchConcepts(ctx, query, types)
+	if err != nil {
+		searchLog.WithError(err).Error("Failed to search concepts")
+		handleReadErrors(err, searchLog, w)
+		return
+	}
+
+	allowed := make(map[string]struct{}, len(types))
+	for _, conceptType := range types {
+		allowed[conceptType] = struct{}{}
+	}
+	response := ConceptsResponse{Concepts: make([]concept.Concept, 0, len(concepts))}
+	for _, c := range concepts {
+		if _, found := allowed[c.Type]; found {
+			response.Concepts = append(response.Concepts, c)
+		}
+	}
+
+	err = json.NewEncoder(w).Encode(&response)
+	if err != nil {
+		searchLog.WithError(err).Error("Failed to encode response")
+		handleReadErrors(err, searchLog, w)
+	}
+}
+
+// searchConceptTypes returns the concept types to search, which are the requested ones
+// if they can all be annotated with the given predicate, or any predicate if it is empty.
+func searchConceptTypes(predicate string, requested []string) ([]string, error) {
+	allowed := mapper.ConceptTypesForPredicate(predicate)
+	if allowed == nil {
+		return nil, fmt.Errorf("invalid param predicate: %s ", predicate)
+	}
+	if len(requested) == 0 {
+		return allowed, nil
+	}
+
+	for _, conceptType := range requested {
+		if !containsString(allowed, conceptType) {
+			return nil, fmt.Errorf("invalid param type: %s cannot be annotated", conceptType)
+		}
+	}
+	return requested, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,272 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Financial-Times/draft-annotations-api/concept"
+	"github.com/Financial-Times/draft-annotations-api/handler"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/husobee/vestigo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	conceptPersonType = "http://www.ft.com/ontology/person/Person"
+	conceptTopicType  = "http://www.ft.com/ontology/Topic"
+	conceptBrandType  = "http://www.ft.com/ontology/product/Brand"
+	conceptGenreType  = "http://www.ft.com/ontology/Genre"
+)
+
+type ConceptSearchAPIMock struct {
+	mock.Mock
+}
+
+func (m *ConceptSearchAPIMock) SearchConcepts(ctx context.Context, query string, types []string) ([]concept.Concept, error) {
+	args := m.Called(ctx, query, types)
+	var concepts []concept.Concept
+	if v := args.Get(0); v != nil {
+		concepts = v.([]concept.Concept)
+	}
+	return concepts, args.Error(1)
+}
+
+func newConceptsRouter(opts ...handler.Option) *vestigo.Router {
+	h := handler.New(new(RWMock), new(AnnotationsAPIMock), nil, new(AugmenterMock), time.Second, opts...)
+	r := vestigo.NewRouter()
+	r.Get("/drafts/concepts/search", h.SearchConcepts)
+	return r
+}
+
+func newConceptSearchRequest(params url.Values) *http.Request {
+	req := httptest.NewRequest("GET", "/drafts/concepts/search?"+params.Encode(), nil)
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	return req
+}
+
+func TestSearchConcepts(t *testing.T) {
+	person := concept.Concept{ID: patchConceptA, Type: conceptPersonType, PrefLabel: "Barack H. Obama"}
+	topic := concept.Concept{ID: patchConceptB, Type: conceptTopicType, PrefLabel: "Obamacare"}
+
+	searchAPI := new(ConceptSearchAPIMock)
+	searchAPI.On("SearchConcepts", mock.Anything, "obama", []string{conceptPersonType}).Return([]concept.Concept{person, topic}, nil)
+
+	w := httptest.NewRecorder()
+	newConceptsRouter(handler.WithConceptSearch(searchAPI)).ServeHTTP(w, newConceptSearchRequest(url.Values{
+		"q":         {"obama"},
+		"predicate": {"http://www.ft.com/ontology/annotation/hasAuthor"},
+	}))
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	actual := handler.ConceptsResponse{}
+	err := json.NewDecoder(resp.Body).Decode(&actual)
+	assert.NoError(t, err)
+	assert.Equal(t, []concept.Concept{person}, actual.Concepts)
+
+	searchAPI.AssertExpectations(t)
+}
+
+func TestSearchConceptsTypes(t *testing.T) {
+	tests := map[string]struct {
+		params        url.Values
+		expectedTypes []string
+	}{
+		"any predicate": {
+			params:        url.Values{"q": {"ft"}},
+			expectedTypes: []string{conceptPersonType, "http://www.ft.com/ontology/organisation/Organisation", "http://www.ft.com/ontology/company/PublicCompany", "http://www.ft.com/ontology/Location", conceptTopicType, conceptBrandType, conceptGenreType},
+		},
+		"predicate": {
+			params:        url.Values{"q": {"ft"}, "predicate": {"http://www.ft.com/ontology/classification/isClassifiedBy"}},
+			expectedTypes: []string{conceptBrandType, conceptGenreType},
+		},
+		"type allowed for the predicate": {
+			params:        url.Values{"q": {"ft"}, "predicate": {"http://www.ft.com/ontology/classification/isClassifiedBy"}, "type": {conceptBrandType}},
+			expectedTypes: []string{conceptBrandType},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			searchAPI := new(ConceptSearchAPIMock)
+			searchAPI.On("SearchConcepts", mock.Anything, "ft", test.expectedTypes).Return([]concept.Concept{}, nil)
+
+			w := httptest.NewRecorder()
+			newConceptsRouter(handler.WithConceptSearch(searchAPI)).ServeHTTP(w, newConceptSearchRequest(test.params))
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			searchAPI.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSearchConceptsInvalidParams(t *testing.T) {
+	tests := map[string]url.Values{
+		"missing query":     {"predicate": {"http://www.ft.com/ontology/annotation/about"}},
+		"blank query":       {"q": {"  "}},
+		"invalid predicate": {"q": {"ft"}, "predicate": {"http://www.ft.com/ontology/annotation/majorMentions"}},
+		"type not allowed":  {"q": {"ft"}, "predicate": {"http://www.ft.com/ontology/annotation/hasAuthor"}, "type": {conceptTopicType}},
+	}
+
+	for name, params := range tests {
+		t.Run(name, func(t *testing.T) {
+			searchAPI := new(ConceptSearchAPIMock)
+
+			w := httptest.NewRecorder()
+			newConceptsRouter(handler.WithConceptSearch(searchAPI)).ServeHTTP(w, newConceptSearchRequest(params))
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			searchAPI.AssertNotCalled(t, "SearchConcepts", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestSearchConceptsError(t *testing.T) {
+	searchAPI := new(ConceptSearchAPIMock)
+	searchAPI.On("SearchConcepts", mock.Anything, "obama", mock.Anything).Return(nil, errors.New("concept search failed"))
+
+	w := httptest.NewRecorder()
+	newConceptsRouter(handler.WithConceptSearch(searchAPI)).ServeHTTP(w, newConceptSearchRequest(url.Values{"q": {"obama"}}))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestSearchConceptsNotEnabled(t *testing.T) {
+	w := httptest.NewRecorder()
+	newConceptsRouter().ServeHTTP(w, newConceptSearchRequest(url.Values{"q": {"obama"}}))
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+}
+
+type ConceptReadAPIMock struct {
+	mock.Mock
+}
+
+func (m *ConceptReadAPIMock) GetConceptsByIDs(ctx context.Context, ids []string) (map[string]concept.Concept, error) {
+	args := m.Called(ctx, ids)
+	var concepts map[string]concept.Concept
+	if v := args.Get(0); v != nil {
+		concepts = v.(map[string]concept.Concept)
+	}
+	return concepts, args.Error(1)
+}
+
+func (m *ConceptReadAPIMock) GTG() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *ConceptReadAPIMock) Endpoint() string {
+	args := m.Called()
+	return args.String(0)
+}
+
+func newConceptLookupRequest(query string) *http.Request {
+	req := httptest.NewRequest("GET", "/drafts/concepts?"+query, nil)
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	return req
+}
+
+func newConceptLookupRouter(opts ...handler.Option) *vestigo.Router {
+	h := handler.New(new(RWMock), new(AnnotationsAPIMock), nil, new(AugmenterMock), time.Second, opts...)
+	r := vestigo.NewRouter()
+	r.Get("/drafts/concepts", h.ReadConcepts)
+	return r
+}
+
+func TestReadConcepts(t *testing.T) {
+	conceptA := concept.Concept{ID: patchConceptA, Type: conceptPersonType, PrefLabel: "Barack H. Obama"}
+	conceptC := concept.Concept{ID: patchConceptC, Type: conceptTopicType, PrefLabel: "Obamacare"}
+	uuidA := "0a619d71-9af5-3755-90dd-f789b686c67a"
+	uuidB := "838b3fbe-efbc-3cfe-b5c0-d38c046492a4"
+	uuidC := "9577c6d4-b09e-4552-b88f-e52745abe02b"
+
+	conceptRead := new(ConceptReadAPIMock)
+	conceptRead.On("GetConceptsByIDs", mock.Anything, []string{uuidA, uuidB, uuidC}).
+		Return(map[string]concept.Concept{uuidA: conceptA, uuidC: conceptC}, nil)
+
+	w := httptest.NewRecorder()
+	query := url.Values{"ids": {patchConceptA + "," + uuidB, uuidC, uuidA}}.Encode()
+	newConceptLookupRouter(handler.WithConceptRead(conceptRead)).ServeHTTP(w, newConceptLookupRequest(query))
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	actual := handler.ConceptLookupResponse{}
+	err := json.NewDecoder(resp.Body).Decode(&actual)
+	assert.NoError(t, err)
+	assert.Equal(t, []concept.Concept{conceptA, conceptC}, actual.Concepts)
+	assert.Equal(t, []string{uuidB}, actual.Unresolved)
+
+	conceptRead.AssertExpectations(t)
+}
+
+func TestReadConceptsPartialResults(t *testing.T) {
+	conceptA := concept.Concept{ID: patchConceptA, Type: conceptPersonType, PrefLabel: "Barack H. Obama"}
+	uuidA := "0a619d71-9af5-3755-90dd-f789b686c67a"
+	uuidB := "838b3fbe-efbc-3cfe-b5c0-d38c046492a4"
+	uuidC := "9577c6d4-b09e-4552-b88f-e52745abe02b"
+
+	conceptRead := new(ConceptReadAPIMock)
+	conceptRead.On("GetConceptsByIDs", mock.Anything, []string{uuidA, uuidB, uuidC}).
+		Return(map[string]concept.Concept{uuidA: conceptA}, &concept.PartialResultsError{
+			Failed: []concept.BatchError{{IDs: []string{uuidC}, Err: concept.ErrUnexpectedResponse}},
+		})
+
+	w := httptest.NewRecorder()
+	query := url.Values{"ids": {uuidA, uuidB, patchConceptC}}.Encode()
+	newConceptLookupRouter(handler.WithConceptRead(conceptRead)).ServeHTTP(w, newConceptLookupRequest(query))
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	actual := handler.ConceptLookupResponse{}
+	err := json.NewDecoder(resp.Body).Decode(&actual)
+	assert.NoError(t, err)
+	assert.Equal(t, []concept.Concept{conceptA}, actual.Concepts)
+	assert.Equal(t, []string{uuidB}, actual.Unresolved)
+	assert.Equal(t, []string{patchConceptC}, actual.Failed)
+
+	conceptRead.AssertExpectations(t)
+}
+
+func TestReadConceptsInvalidIDs(t *testing.T) {
+	tests := map[string]string{
+		"missing ids": "",
+		"empty ids":   "ids=,",
+		"invalid id":  "ids=not-a-uuid",
+	}
+
+	for name, query := range tests {
+		t.Run(name, func(t *testing.T) {
+			conceptRead := new(ConceptReadAPIMock)
+
+			w := httptest.NewRecorder()
+			newConceptLookupRouter(handler.WithConceptRead(conceptRead)).ServeHTTP(w, newConceptLookupRequest(query))
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			conceptRead.AssertNotCalled(t, "GetConceptsByIDs", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestReadConceptsError(t *testing.T) {
+	conceptRead := new(ConceptReadAPIMock)
+	conceptRead.On("GetConceptsByIDs", mock.Anything, mock.Anything).Return(nil, errors.New("concept read failed"))
+
+	w := httptest.NewRecorder()
+	newConceptLookupRouter(handler.WithConceptRead(conceptRead)).ServeHTTP(w, newConceptLookupRequest("ids=0a619d71-9af5-3755-90dd-f789b686c67a"))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestReadConceptsNotEnabled(t *testing.T) {
+	w := httptest.NewRecorder()
+	newConceptLookupRouter().ServeHTTP(w, newConceptLookupRequest("ids=0a619d71-9af5-3755-90dd-f789b686c67a"))
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/Financial-Times/draft-annotations-api/annotations"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/husobee/vestigo"
+	log "github.com/sirupsen/logrus"
+)
+
+// The diff is augmented with a single batch, keyed by the side of each change: the added and removed annotations
+// have a key each, and each predicate change has its own key, under its own prefix, to find its concept data.
+const (
+	diffAddedKey            = "added"
+	diffRemovedKey          = "removed"
+	diffPredicateChangedKey = "predicateChanged/"
+)
+
+// DiffResponse is the body of the diff response. Unresolved lists the concepts of the changes whose concept data
+// has not been found, which are reported with their predicate and concept ID only.
+type DiffResponse struct {
+	annotations.Diff
+	Unresolved []annotations.UnresolvedConcept `json:"unresolved,omitempty"`
+}
+
+// DiffAnnotations compares the draft annotations of a content with its published annotations.
+// It returns the annotations added to and removed from the draft, and the concepts whose predicates have changed,
+// all of them augmented with recent UPP concept data. If there is no draft, the returned diff is empty.
+func (h *Handler) DiffAnnotations(w http.ResponseWriter, r *http.Request) {
+	contentUUID := vestigo.Param(r, "uuid")
+	tID := tidutils.GetTransactionIDFromRequest(r)
+
+	ctx, cancel := context.WithTimeout(tidutils.TransactionAwareContext(r.Context(), tID), h.timeout)
+	defer cancel()
+
+	readLog := readLogEntry(ctx, contentUUID)
+
+	w.Header().Add("Content-Type", "application/json")
+
+	if err := validateUUID(contentUUID); err != nil {
+		writeMessage(w, "Invalid content UUID: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	diff, hash, err := h.diffAnnotations(ctx, contentUUID, readLog)
+	if err != nil {
+		handleReadErrors(err, readLog, w)
+		return
+	}
+	if hash != "" {
+		w.Header().Set(annotations.DocumentHashHeader, hash)
+	}
+
+	err = json.NewEncoder(w).Encode(&diff)
+	if err != nil {
+		readLog.WithError(err).Error("Failed to encode response")
+		handleReadErrors(err, readLog, w)
+	}
+}
+
+func (h *Handler) diffAnnotations(ctx context.Context, contentUUID string, readLog *log.Entry) (DiffResponse, string, error) {
+	readLog.Info("Reading Annotations from Annotations R/W")
+	draft, hash, hasDraft, err := h.readRW.Read(ctx, contentUUID)
+	if err != nil {
+		return DiffResponse{}, hash, err
+	}
+	if !hasDraft {
+		readLog.Info("Annotations not found, draft is the same as the published annotations")
+		return DiffResponse{Diff: annotations.Compare(nil, nil)}, hash, nil
+	}
+
+	readLog.Info("Retrieving published annotations from UPP")
+	published, err := h.annotationsAPI.GetAll(ctx, contentUUID)
+	if err != nil {
+		var uppErr annotations.UPPError
+		if !errors.As(err, &uppErr) || uppErr.Status() != http.StatusNotFound {
+			return DiffResponse{}, hash, err
+		}
+		readLog.Info("Published annotations not found, all draft annotations are new")
+		published = nil
+	}
+
+	diff := annotations.Compare(
+		h.c14n.Canonicalize(switchToIsClassifiedBy(published)),
+		h.c14n.Canonicalize(switchToIsClassifiedBy(draft.Annotations)),
+	)
+	if diff.IsEmpty() {
+		return DiffResponse{Diff: diff}, hash, nil
+	}
+
+	toAugment := map[string][]annotations.Annotation{
+		diffAddedKey:   diff.Added,
+		diffRemovedKey: diff.Removed,
+	}
+	for _, change := range diff.PredicateChanged {
+		toAugment[diffPredicateChangedKey+change.ConceptId] = []annotations.Annotation{{Predicate: change.To[0], ConceptId: change.ConceptId}}
+	}
+
+	readLog.Info("Augmenting annotations diff with recent UPP data")
+	augmented, unresolved, err := h.annotationsAugmenter.AugmentAnnotationsBatch(ctx, toAugment)
+	if err != nil {
+		readLog.WithError(err).Error("Failed to augment annotations diff")
+		return DiffResponse{}, hash, err
+	}
+
+	// the changes of the unresolved concepts are still changes, so they are kept as they are compared
+	diff.Added = append(augmented[diffAddedKey], annotations.UnresolvedAnnotations(diff.Added, unresolved[diffAddedKey])...)
+	sort.Sort(annotations.NewCanonicalAnnotationSorter(diff.Added))
+	diff.Removed = append(augmented[diffRemovedKey], annotations.UnresolvedAnnotations(diff.Removed, unresolved[diffRemovedKey])...)
+	sort.Sort(annotations.NewCanonicalAnnotationSorter(diff.Removed))
+	for i, change := range diff.PredicateChanged {
+		if concepts := augmented[diffPredicateChangedKey+change.ConceptId]; len(concepts) > 0 {
+			diff.PredicateChanged[i].ConceptId = concepts[0].ConceptId
+			diff.PredicateChanged[i].ApiUrl = concepts[0].ApiUrl
+			diff.PredicateChanged[i].Type = concepts[0].Type
+			diff.PredicateChanged[i].PrefLabel = concepts[0].PrefLabel
+			diff.PredicateChanged[i].IsFTAuthor = concepts[0].IsFTAuthor
+		}
+	}
+	return DiffResponse{Diff: diff, Unresolved: diffUnresolved(unresolved)}, hash, nil
+}
+
+// diffUnresolved returns the unresolved concepts of all the changes of a diff. Each concept is only in one of them,
+// as a concept is either added, removed or annotated on both sides.
+func diffUnresolved(unresolvedBatch map[string][]annotations.UnresolvedConcept) []annotations.UnresolvedConcept {
+	var unresolved []annotations.UnresolvedConcept
+	for _, concepts := range unresolvedBatch {
+		unresolved = append(unresolved, concepts...)
+	}
+	sort.Slice(unresolved, func(i, j int) bool {
+		return unresolved[i].ConceptId < unresolved[j].ConceptId
+	})
+	return unresolved
+}
@@ -0,0 +1,263 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Financial-Times/draft-annotations-api/annotations"
+	"github.com/Financial-Times/draft-annotations-api/handler"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/husobee/vestigo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const diffHasBrand = "http://www.ft.com/ontology/hasBrand"
+
+var diffPrefLabels = map[string]string{
+	patchConceptA: "Concept A",
+	patchConceptB: "Concept B",
+	patchConceptC: "Concept C",
+}
+
+func newDiffRouter(rw *RWMock, annAPI *AnnotationsAPIMock, aug *AugmenterMock) *vestigo.Router {
+	h := handler.New(rw, annAPI, annotations.NewCanonicalizer(annotations.NewCanonicalAnnotationSorter), aug, time.Second)
+	r := vestigo.NewRouter()
+	r.Get("/drafts/content/:uuid/annotations/diff", h.DiffAnnotations)
+	return r
+}
+
+func newLabellingAugmenter() *AugmenterMock {
+	return &AugmenterMock{
+		augmentBatch: func(ctx context.Context, batch map[string][]annotations.Annotation) (map[string][]annotations.Annotation, error) {
+			augmented := make(map[string][]annotations.Annotation)
+			for key, list := range batch {
+				augmented[key] = make([]annotations.Annotation, 0)
+				for _, ann := range list {
+					ann.PrefLabel = diffPrefLabels[ann.ConceptId]
+					augmented[key] = append(augmented[key], ann)
+				}
+			}
+			return augmented, nil
+		},
+	}
+}
+
+func newDiffRequest() *http.Request {
+	req := httptest.NewRequest("GET", "/drafts/content/"+patchContentUUID+"/annotations/diff", nil)
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	return req
+}
+
+func TestDiffAnnotations(t *testing.T) {
+	draft := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: diffHasBrand, ConceptId: patchConceptA},
+		{Predicate: patchAbout, ConceptId: patchConceptB},
+		{Predicate: patchMentions, ConceptId: patchConceptB},
+	}}
+	published := []annotations.Annotation{
+		{Predicate: "http://www.ft.com/ontology/classification/isClassifiedBy", ConceptId: patchConceptA, PrefLabel: "Old label"},
+		{Predicate: patchMentions, ConceptId: patchConceptB},
+		{Predicate: patchMentions, ConceptId: patchConceptC},
+	}
+
+	rw := new(RWMock)
+	rw.On("Read", mock.Anything, patchContentUUID).Return(draft, "draft-hash", true, nil)
+	annAPI := new(AnnotationsAPIMock)
+	annAPI.On("GetAll", mock.Anything, patchContentUUID).Return(published, nil)
+
+	r := newDiffRouter(rw, annAPI, newLabellingAugmenter())
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newDiffRequest())
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "draft-hash", resp.Header.Get(annotations.DocumentHashHeader))
+
+	actual := annotations.Diff{}
+	err := json.NewDecoder(resp.Body).Decode(&actual)
+	assert.NoError(t, err)
+	assert.Equal(t, annotations.Diff{
+		Added: []annotations.Annotation{},
+		Removed: []annotations.Annotation{
+			{Predicate: patchMentions, ConceptId: patchConceptC, PrefLabel: "Concept C"},
+		},
+		PredicateChanged: []annotations.PredicateChange{
+			{
+				ConceptId: patchConceptB,
+				PrefLabel: "Concept B",
+				From:      []string{patchMentions},
+				To:        []string{patchAbout, patchMentions},
+			},
+		},
+	}, actual)
+
+	rw.AssertExpectations(t)
+	annAPI.AssertExpectations(t)
+}
+
+func TestDiffAnnotationsWithUnresolvedConcepts(t *testing.T) {
+	draft := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+		{Predicate: patchAbout, ConceptId: patchConceptB},
+	}}
+	published := []annotations.Annotation{
+		{Predicate: patchMentions, ConceptId: patchConceptB},
+		{Predicate: patchMentions, ConceptId: patchConceptC},
+	}
+
+	rw := new(RWMock)
+	rw.On("Read", mock.Anything, patchContentUUID).Return(draft, "draft-hash", true, nil)
+	annAPI := new(AnnotationsAPIMock)
+	annAPI.On("GetAll", mock.Anything, patchContentUUID).Return(published, nil)
+
+	// none of the concepts are found
+	aug := &AugmenterMock{
+		augmentBatch: func(_ context.Context, batch map[string][]annotations.Annotation) (map[string][]annotations.Annotation, error) {
+			augmented := make(map[string][]annotations.Annotation)
+			for key := range batch {
+				augmented[key] = []annotations.Annotation{}
+			}
+			return augmented, nil
+		},
+		unresolvedBatch: map[string][]annotations.UnresolvedConcept{
+			"added":                             {{ConceptId: patchConceptA, Predicates: []string{patchAbout}}},
+			"removed":                           {{ConceptId: patchConceptC, Predicates: []string{patchMentions}}},
+			"predicateChanged/" + patchConceptB: {{ConceptId: patchConceptB, Predicates: []string{patchAbout}}},
+		},
+	}
+
+	r := newDiffRouter(rw, annAPI, aug)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newDiffRequest())
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	actual := handler.DiffResponse{}
+	err := json.NewDecoder(resp.Body).Decode(&actual)
+	assert.NoError(t, err)
+	assert.Equal(t, []annotations.Annotation{{Predicate: patchAbout, ConceptId: patchConceptA}}, actual.Added)
+	assert.Equal(t, []annotations.Annotation{{Predicate: patchMentions, ConceptId: patchConceptC}}, actual.Removed)
+	assert.Equal(t, []annotations.PredicateChange{
+		{ConceptId: patchConceptB, From: []string{patchMentions}, To: []string{patchAbout}},
+	}, actual.PredicateChanged)
+	assert.ElementsMatch(t, []annotations.UnresolvedConcept{
+		{ConceptId: patchConceptA, Predicates: []string{patchAbout}},
+		{ConceptId: patchConceptB, Predicates: []string{patchAbout}},
+		{ConceptId: patchConceptC, Predicates: []string{patchMentions}},
+	}, actual.Unresolved)
+}
+
+func TestDiffAnnotationsWithoutPublishedAnnotations(t *testing.T) {
+	draft := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+	}}
+
+	rw := new(RWMock)
+	rw.On("Read", mock.Anything, patchContentUUID).Return(draft, "draft-hash", true, nil)
+	annAPI := new(AnnotationsAPIMock)
+	annAPI.On("GetAll", mock.Anything, patchContentUUID).
+		Return([]annotations.Annotation{}, annotations.NewUPPError(annotations.NoAnnotationsMsg, http.StatusNotFound, nil))
+
+	r := newDiffRouter(rw, annAPI, newLabellingAugmenter())
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newDiffRequest())
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	actual := annotations.Diff{}
+	err := json.NewDecoder(resp.Body).Decode(&actual)
+	assert.NoError(t, err)
+	assert.Equal(t, []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA, PrefLabel: "Concept A"},
+	}, actual.Added)
+	assert.Empty(t, actual.Removed)
+	assert.Empty(t, actual.PredicateChanged)
+}
+
+func TestDiffAnnotationsWithoutDraft(t *testing.T) {
+	rw := new(RWMock)
+	rw.On("Read", mock.Anything, patchContentUUID).Return(nil, "", false, nil)
+	annAPI := new(AnnotationsAPIMock)
+	aug := new(AugmenterMock)
+
+	r := newDiffRouter(rw, annAPI, aug)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newDiffRequest())
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	actual := annotations.Diff{}
+	err := json.NewDecoder(resp.Body).Decode(&actual)
+	assert.NoError(t, err)
+	assert.True(t, actual.IsEmpty())
+
+	annAPI.AssertNotCalled(t, "GetAll", mock.Anything, mock.Anything)
+	aug.AssertNotCalled(t, "AugmentAnnotationsBatch", mock.Anything, mock.Anything)
+}
+
+func TestUnhappyDiffAnnotations(t *testing.T) {
+	draft := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+	}}
+
+	tests := map[string]struct {
+		contentUUID    string
+		rwErr          error
+		uppErr         error
+		augmentErr     error
+		expectedStatus int
+	}{
+		"invalid content UUID": {
+			contentUUID:    "not-a-uuid",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"RW error": {
+			contentUUID:    patchContentUUID,
+			rwErr:          errors.New("computer says no"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+		"UPP error": {
+			contentUUID:    patchContentUUID,
+			uppErr:         annotations.NewUPPError("UPP is down", http.StatusServiceUnavailable, nil),
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		"augmenter error": {
+			contentUUID:    patchContentUUID,
+			augmentErr:     errors.New("concept search is down"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rw := new(RWMock)
+			rw.On("Read", mock.Anything, test.contentUUID).Return(draft, "draft-hash", true, test.rwErr)
+			annAPI := new(AnnotationsAPIMock)
+			annAPI.On("GetAll", mock.Anything, test.contentUUID).Return([]annotations.Annotation{}, test.uppErr)
+			aug := new(AugmenterMock)
+			aug.On("AugmentAnnotationsBatch", mock.Anything, mock.Anything).Return(nil, test.augmentErr)
+
+			r := newDiffRouter(rw, annAPI, aug)
+
+			req := httptest.NewRequest("GET", "/drafts/content/"+test.contentUUID+"/annotations/diff", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			resp := w.Result()
+			defer resp.Body.Close()
+			assert.Equal(t, test.expectedStatus, resp.StatusCode)
+		})
+	}
+}
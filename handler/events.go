@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Financial-Times/draft-annotations-api/annotations"
+	"github.com/Financial-Times/draft-annotations-api/events"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/husobee/vestigo"
+	log "github.com/sirupsen/logrus"
+)
+
+// eventsKeepAlive is the interval of the comments sent on idle event streams,
+// so that proxies do not close them.
+const eventsKeepAlive = 30 * time.Second
+
+// StreamEvents streams the changes to the draft annotations for a given content uuid as Server-Sent Events,
+// until the client disconnects. Each event has the type of the change as name and the new hash as ID.
+func (h *Handler) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	contentUUID := vestigo.Param(r, "uuid")
+	tID := tidutils.GetTransactionIDFromRequest(r)
+	streamLog := log.WithField(tidutils.TransactionIDKey, tID).WithField("uuid", contentUUID)
+
+	if err := validateUUID(contentUUID); err != nil {
+		w.Header().Add("Content-Type", "application/json")
+		writeMessage(w, "Invalid content UUID: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if h.events == nil {
+		w.Header().Add("Content-Type", "application/json")
+		writeMessage(w, "Draft annotations events are not enabled", http.StatusNotImplemented)
+		return
+	}
+
+	stream, unsubscribe := h.events.Subscribe(contentUUID)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	rc := http.NewResponseController(w)
+	if err := rc.Flush(); err != nil {
+		streamLog.WithError(err).Error("Streaming of draft annotations events is not supported")
+		return
+	}
+	streamLog.Info("Streaming draft annotations events")
+
+	keepAlive := time.NewTicker(eventsKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		var err error
+		select {
+		case <-r.Context().Done():
+			streamLog.Info("Client closed the draft annotations events stream")
+			return
+		case event, ok := <-stream:
+			if !ok {
+				return
+			}
+			err = writeEvent(w, event)
+		case <-keepAlive.C:
+			_, err = fmt.Fprint(w, ": keep-alive\n\n")
+		}
+		if err == nil {
+			err = rc.Flush()
+		}
+		if err != nil {
+			streamLog.WithError(err).Warn("Failed to write draft annotations event")
+			return
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, event events.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.Hash, event.Type, data)
+	return err
+}
+
+// publishEvent notifies the subscribers to the events of the given content, the webhooks and the notifications feed
+// of a change to its draft annotations, if enabled. The notifications are best effort, their failures are only logged.
+func (h *Handler) publishEvent(ctx context.Context, eventType string, contentUUID string, previousHash string, newHash string, changes *annotations.Diff, writeLog *log.Entry) {
+	if h.events == nil && h.webhooks == nil && h.notifications == nil {
+		return
+	}
+
+	event := events.Event{
+		Type:         eventType,
+		ContentUUID:  contentUUID,
+		Hash:         newHash,
+		PreviousHash: previousHash,
+		Timestamp:    time.Now().UTC(),
+		Changes:      changes,
+	}
+	event.TransactionID, _ = tidutils.GetTransactionIDFromContext(ctx)
+	if h.events != nil {
+		if err := h.events.Publish(ctx, event); err != nil {
+			writeLog.WithError(err).Warn("Failed to publish draft annotations event")
+		}
+	}
+	if h.webhooks != nil {
+		if err := h.webhooks.Publish(ctx, event); err != nil {
+			writeLog.WithError(err).Warn("Failed to queue draft annotations webhook")
+		}
+	}
+	h.recordNotification(ctx, event, writeLog)
+}
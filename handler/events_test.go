@@ -0,0 +1,206 @@
+package handler_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Financial-Times/draft-annotations-api/annotations"
+	"github.com/Financial-Times/draft-annotations-api/events"
+	"github.com/Financial-Times/draft-annotations-api/handler"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/husobee/vestigo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newEventsServer(rw *RWMock, hub events.Hub) *httptest.Server {
+	aug := &AugmenterMock{
+		augment: func(ctx context.Context, depletedAnnotations []annotations.Annotation) ([]annotations.Annotation, error) {
+			return depletedAnnotations, nil
+		},
+	}
+	var opts []handler.Option
+	if hub != nil {
+		opts = append(opts, handler.WithEvents(hub))
+	}
+	h := handler.New(rw, new(AnnotationsAPIMock), annotations.NewCanonicalizer(annotations.NewCanonicalAnnotationSorter), aug, time.Second, opts...)
+	r := vestigo.NewRouter()
+	r.Put("/drafts/content/:uuid/annotations", h.WriteAnnotations)
+	r.Delete("/drafts/content/:uuid/annotations", h.DiscardDraftAnnotations)
+	r.Get("/drafts/content/:uuid/annotations/events", h.StreamEvents)
+	return httptest.NewServer(r)
+}
+
+// readEvent reads the next event from a Server-Sent Events stream, skipping comments.
+func readEvent(t *testing.T, stream *bufio.Reader) (string, string, events.Event) {
+	var id, name string
+	var event events.Event
+	for {
+		line, err := stream.ReadString('\n')
+		if !assert.NoError(t, err) {
+			return id, name, event
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			return id, name, event
+		case strings.HasPrefix(line, "id: "):
+			id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			assert.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event))
+		}
+	}
+}
+
+func TestStreamEvents(t *testing.T) {
+	before := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+	}}
+	after := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+		{Predicate: patchMentions, ConceptId: patchConceptB},
+	}}
+
+	rw := new(RWMock)
+	rw.On("Read", mock.Anything, patchContentUUID).Return(before, "old-hash", true, nil)
+	rw.On("Write", mock.Anything, patchContentUUID, after, "old-hash").Return("new-hash", nil)
+	rw.On("Delete", mock.Anything, patchContentUUID, "new-hash").Return(nil)
+
+	s := newEventsServer(rw, events.NewMemoryHub(4))
+	defer s.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", s.URL+"/drafts/content/"+patchContentUUID+"/annotations/events", nil)
+	assert.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	stream := bufio.NewReader(resp.Body)
+
+	writeReq, err := http.NewRequest("PUT", s.URL+"/drafts/content/"+patchContentUUID+"/annotations", strings.NewReader(`{"annotations":[
+		{"predicate":"`+patchAbout+`","id":"`+patchConceptA+`"},
+		{"predicate":"`+patchMentions+`","id":"`+patchConceptB+`"}
+	]}`))
+	assert.NoError(t, err)
+	writeReq.Header.Set(tidutils.TransactionIDHeader, testTID)
+	writeReq.Header.Set(annotations.PreviousDocumentHashHeader, "old-hash")
+	writeResp, err := http.DefaultClient.Do(writeReq)
+	assert.NoError(t, err)
+	writeResp.Body.Close()
+	assert.Equal(t, http.StatusOK, writeResp.StatusCode)
+
+	id, name, event := readEvent(t, stream)
+	assert.Equal(t, "new-hash", id)
+	assert.Equal(t, events.Saved, name)
+	assert.Equal(t, patchContentUUID, event.ContentUUID)
+	assert.Equal(t, "old-hash", event.PreviousHash)
+	assert.Equal(t, testTID, event.TransactionID)
+	assert.Equal(t, &annotations.Diff{
+		Added:            []annotations.Annotation{{Predicate: patchMentions, ConceptId: patchConceptB}},
+		Removed:          []annotations.Annotation{},
+		PredicateChanged: []annotations.PredicateChange{},
+	}, event.Changes)
+
+	discardReq, err := http.NewRequest("DELETE", s.URL+"/drafts/content/"+patchContentUUID+"/annotations", nil)
+	assert.NoError(t, err)
+	discardReq.Header.Set(annotations.PreviousDocumentHashHeader, "new-hash")
+	discardResp, err := http.DefaultClient.Do(discardReq)
+	assert.NoError(t, err)
+	discardResp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, discardResp.StatusCode)
+
+	_, name, event = readEvent(t, stream)
+	assert.Equal(t, events.Discarded, name)
+	assert.Equal(t, "new-hash", event.PreviousHash)
+	assert.Empty(t, event.Hash)
+	assert.Nil(t, event.Changes)
+
+	rw.AssertExpectations(t)
+}
+
+func TestStreamEventsErrors(t *testing.T) {
+	tests := map[string]struct {
+		contentUUID    string
+		hub            events.Hub
+		expectedStatus int
+	}{
+		"invalid content UUID": {
+			contentUUID:    "foo",
+			hub:            events.NewMemoryHub(1),
+			expectedStatus: http.StatusBadRequest,
+		},
+		"events disabled": {
+			contentUUID:    patchContentUUID,
+			expectedStatus: http.StatusNotImplemented,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := newEventsServer(new(RWMock), test.hub)
+			defer s.Close()
+
+			resp, err := http.Get(s.URL + "/drafts/content/" + test.contentUUID + "/annotations/events")
+			assert.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, test.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+type publisherMock struct {
+	events []events.Event
+}
+
+func (p *publisherMock) Publish(_ context.Context, event events.Event) error {
+	p.events = append(p.events, event)
+	return nil
+}
+
+func TestWriteAnnotationsPublishesToWebhooks(t *testing.T) {
+	after := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+	}}
+
+	rw := new(RWMock)
+	rw.On("Read", mock.Anything, patchContentUUID).Return(after, "old-hash", true, nil)
+	rw.On("Write", mock.Anything, patchContentUUID, after, "old-hash").Return("new-hash", nil)
+	aug := &AugmenterMock{
+		augment: func(ctx context.Context, depletedAnnotations []annotations.Annotation) ([]annotations.Annotation, error) {
+			return depletedAnnotations, nil
+		},
+	}
+	webhooks := &publisherMock{}
+
+	h := handler.New(rw, new(AnnotationsAPIMock), annotations.NewCanonicalizer(annotations.NewCanonicalAnnotationSorter), aug, time.Second, handler.WithWebhooks(webhooks))
+	r := vestigo.NewRouter()
+	r.Put("/drafts/content/:uuid/annotations", h.WriteAnnotations)
+
+	req := httptest.NewRequest("PUT", "/drafts/content/"+patchContentUUID+"/annotations", strings.NewReader(`{"annotations":[{"predicate":"`+patchAbout+`","id":"`+patchConceptA+`"}]}`))
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	req.Header.Set(annotations.PreviousDocumentHashHeader, "old-hash")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+	assert.Len(t, webhooks.events, 1)
+	event := webhooks.events[0]
+	assert.Equal(t, events.Saved, event.Type)
+	assert.Equal(t, patchContentUUID, event.ContentUUID)
+	assert.Equal(t, "old-hash", event.PreviousHash)
+	assert.Equal(t, "new-hash", event.Hash)
+	assert.True(t, event.Changes.IsEmpty())
+
+	rw.AssertExpectations(t)
+}
@@ -7,49 +7,184 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/Financial-Times/draft-annotations-api/annotations"
+	"github.com/Financial-Times/draft-annotations-api/audit"
+	"github.com/Financial-Times/draft-annotations-api/concept"
+	"github.com/Financial-Times/draft-annotations-api/events"
+	"github.com/Financial-Times/draft-annotations-api/history"
 	"github.com/Financial-Times/draft-annotations-api/mapper"
+	"github.com/Financial-Times/draft-annotations-api/notifications"
 	tidutils "github.com/Financial-Times/transactionid-utils-go"
 	"github.com/google/uuid"
 	"github.com/husobee/vestigo"
 	log "github.com/sirupsen/logrus"
 )
 
+// PartiallyAugmentedHeader is set to true on the read responses whose annotations could not all be augmented
+// with the concept data, when the degraded reads are enabled.
+const PartiallyAugmentedHeader = "Partially-Augmented"
+
+// VersionHashHeader holds the hash of the version read from the draft history. Such a read has no Document-Hash,
+// because the hash of a previous version is not to be sent back as the Previous-Document-Hash of a write.
+const VersionHashHeader = "Version-Hash"
+
 // AnnotationsAPI interface encapsulates logic for getting published annotations from API
 type AnnotationsAPI interface {
 	GetAll(context.Context, string) ([]annotations.Annotation, error)
 	GetAllButV2(context.Context, string) ([]annotations.Annotation, error)
+	GetImplicit(context.Context, string) ([]annotations.Annotation, error)
+}
+
+// SuggestionsAPI interface encapsulates logic for getting the annotations suggested for a content
+type SuggestionsAPI interface {
+	GetSuggestions(context.Context, string) ([]annotations.Annotation, error)
 }
 
 // Interface for the annotations augmenter (currently only functionality in the annotations package)
 type Augmenter interface {
-	AugmentAnnotations(ctx context.Context, depletedAnnotations []annotations.Annotation) ([]annotations.Annotation, error)
+	AugmentAnnotations(ctx context.Context, depletedAnnotations []annotations.Annotation) ([]annotations.Annotation, []annotations.UnresolvedConcept, error)
+	AugmentAnnotationsBatch(ctx context.Context, depletedAnnotations map[string][]annotations.Annotation) (map[string][]annotations.Annotation, map[string][]annotations.UnresolvedConcept, error)
+	AugmentAnnotationsOrDegrade(ctx context.Context, depletedAnnotations []annotations.Annotation) ([]annotations.Annotation, []annotations.UnresolvedConcept, bool)
+	AugmentAnnotationsBatchOrDegrade(ctx context.Context, depletedAnnotations map[string][]annotations.Annotation) (map[string][]annotations.Annotation, map[string][]annotations.UnresolvedConcept, bool)
 }
 
 // Handler provides endpoints for reading annotations - draft or published, and writing draft annotations.
 type Handler struct {
 	annotationsRW        annotations.RW
+	readRW               annotations.RW
 	annotationsAPI       AnnotationsAPI
 	c14n                 *annotations.Canonicalizer
 	annotationsAugmenter Augmenter
 	timeout              time.Duration
+	history              history.Store
+	audit                audit.Sink
+	events               events.Hub
+	webhooks             events.Publisher
+	notifications        notifications.Store
+	publisher            Publisher
+	suggestionsAPI       SuggestionsAPI
+	conceptSearch        ConceptSearchAPI
+	conceptRead          concept.ReadAPI
+	degradedReads        bool
+	strictConcepts       bool
 }
 
-// New initializes Handler.
-func New(rw annotations.RW, annotationsAPI AnnotationsAPI, c14n *annotations.Canonicalizer, augmenter Augmenter, httpTimeout time.Duration) *Handler {
-	return &Handler{
-		rw,
-		annotationsAPI,
-		c14n,
-		augmenter,
-		httpTimeout,
+// Option configures optional features of the Handler.
+type Option func(*Handler)
+
+// WithHistory records every saved version of the draft annotations in the given store,
+// enabling the versions endpoint and the time-travel reads.
+func WithHistory(store history.Store) Option {
+	return func(h *Handler) {
+		h.history = store
 	}
 }
 
+// WithAudit records an audit entry for every change to the draft annotations in the given sink,
+// enabling the audit endpoint if the sink can be read back.
+func WithAudit(sink audit.Sink) Option {
+	return func(h *Handler) {
+		h.audit = sink
+	}
+}
+
+// WithEvents publishes an event for every change to the draft annotations in the given hub,
+// enabling the events endpoint.
+func WithEvents(hub events.Hub) Option {
+	return func(h *Handler) {
+		h.events = hub
+	}
+}
+
+// WithWebhooks publishes an event for every change to the draft annotations to the given webhooks publisher.
+func WithWebhooks(publisher events.Publisher) Option {
+	return func(h *Handler) {
+		h.webhooks = publisher
+	}
+}
+
+// WithNotifications records a notification for every change to the draft annotations in the given store,
+// enabling the notifications feed.
+func WithNotifications(store notifications.Store) Option {
+	return func(h *Handler) {
+		h.notifications = store
+	}
+}
+
+// WithPublisher enables the publish endpoint, sending the draft annotations to the given publisher.
+func WithPublisher(publisher Publisher) Option {
+	return func(h *Handler) {
+		h.publisher = publisher
+	}
+}
+
+// WithSuggestions enables the includeSuggestions read mode, getting the suggested annotations from the given API.
+func WithSuggestions(suggestionsAPI SuggestionsAPI) Option {
+	return func(h *Handler) {
+		h.suggestionsAPI = suggestionsAPI
+	}
+}
+
+// WithConceptSearch enables the concept search endpoint, searching the concepts with the given API.
+func WithConceptSearch(conceptSearch ConceptSearchAPI) Option {
+	return func(h *Handler) {
+		h.conceptSearch = conceptSearch
+	}
+}
+
+// WithDegradedReads makes the reads of the annotations return them partially augmented, with the
+// PartiallyAugmentedHeader, instead of failing when the concept data cannot be read. Writes still fail.
+func WithDegradedReads() Option {
+	return func(h *Handler) {
+		h.degradedReads = true
+	}
+}
+
+// WithStrictConcepts makes the writes of annotations whose concept data has not been found fail with
+// 422 Unprocessable Entity. Otherwise they are saved and their concepts are reported as unresolved.
+func WithStrictConcepts() Option {
+	return func(h *Handler) {
+		h.strictConcepts = true
+	}
+}
+
+// WithReadRW makes the read endpoints read the draft annotations with the given RW, e.g. one coalescing
+// concurrent reads. The writes keep reading the current draft with the RW given to New,
+// so that they never get a draft older than a write which has completed.
+func WithReadRW(rw annotations.RW) Option {
+	return func(h *Handler) {
+		h.readRW = rw
+	}
+}
+
+// WithConceptRead enables the concept lookup endpoint, reading the concepts with the given API.
+func WithConceptRead(conceptRead concept.ReadAPI) Option {
+	return func(h *Handler) {
+		h.conceptRead = conceptRead
+	}
+}
+
+// New initializes Handler.
+func New(rw annotations.RW, annotationsAPI AnnotationsAPI, c14n *annotations.Canonicalizer, augmenter Augmenter, httpTimeout time.Duration, opts ...Option) *Handler {
+	h := &Handler{
+		annotationsRW:        rw,
+		readRW:               rw,
+		annotationsAPI:       annotationsAPI,
+		c14n:                 c14n,
+		annotationsAugmenter: augmenter,
+		timeout:              httpTimeout,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
 // DeleteAnnotation deletes a given annotation for a given content uuid.
 // It gets the annotations only from UPP skipping V2 annotations because they are not editorially curated.
 func (h *Handler) DeleteAnnotation(w http.ResponseWriter, r *http.Request) {
@@ -59,7 +194,7 @@ func (h *Handler) DeleteAnnotation(w http.ResponseWriter, r *http.Request) {
 	conceptID := mapper.TransformConceptID("/" + vestigo.Param(r, "cuuid"))
 
 	tID := tidutils.GetTransactionIDFromRequest(r)
-	ctx := tidutils.TransactionAwareContext(context.Background(), tID)
+	ctx := auditContext(tidutils.TransactionAwareContext(context.Background(), tID), r)
 	writeLog := log.WithField(tidutils.TransactionIDKey, tID).WithField("uuid", contentUUID)
 
 	oldHash := r.Header.Get(annotations.PreviousDocumentHashHeader)
@@ -90,6 +225,39 @@ func (h *Handler) DeleteAnnotation(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set(annotations.DocumentHashHeader, newHash)
 }
 
+// DiscardDraftAnnotations deletes the draft annotations for a given content uuid,
+// so that subsequent reads return the published annotations again.
+func (h *Handler) DiscardDraftAnnotations(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	contentUUID := vestigo.Param(r, "uuid")
+
+	tID := tidutils.GetTransactionIDFromRequest(r)
+	ctx := auditContext(tidutils.TransactionAwareContext(context.Background(), tID), r)
+	writeLog := log.WithField(tidutils.TransactionIDKey, tID).WithField("uuid", contentUUID)
+
+	oldHash := r.Header.Get(annotations.PreviousDocumentHashHeader)
+
+	if err := validateUUID(contentUUID); err != nil {
+		handleWriteErrors("Invalid content UUID", err, writeLog, w, http.StatusBadRequest)
+		return
+	}
+
+	before := h.readPreviousDraft(ctx, contentUUID, oldHash)
+
+	writeLog.Debug("Deleting draft from annotations RW...")
+	err := h.annotationsRW.Delete(ctx, contentUUID, oldHash)
+	if err != nil {
+		handleWriteErrors("Error deleting draft annotations", h.staleHashError(ctx, contentUUID, err), writeLog, w, http.StatusInternalServerError)
+		return
+	}
+	// the discarded draft is recorded as the removal of all its annotations
+	h.recordAudit(ctx, contentUUID, before, nil, "", writeLog)
+	h.publishEvent(ctx, events.Discarded, contentUUID, oldHash, "", nil, writeLog)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // AddAnnotation adds an annotation for a specific content uuid.
 // It gets the annotations only from UPP skipping V2 annotations because they are not editorially curated.
 func (h *Handler) AddAnnotation(w http.ResponseWriter, r *http.Request) {
@@ -98,7 +266,7 @@ func (h *Handler) AddAnnotation(w http.ResponseWriter, r *http.Request) {
 	contentUUID := vestigo.Param(r, "uuid")
 
 	tID := tidutils.GetTransactionIDFromRequest(r)
-	ctx := tidutils.TransactionAwareContext(context.Background(), tID)
+	ctx := auditContext(tidutils.TransactionAwareContext(context.Background(), tID), r)
 	writeLog := log.WithField(tidutils.TransactionIDKey, tID).WithField("uuid", contentUUID)
 
 	oldHash := r.Header.Get(annotations.PreviousDocumentHashHeader)
@@ -145,6 +313,8 @@ func (h *Handler) AddAnnotation(w http.ResponseWriter, r *http.Request) {
 
 // ReadAnnotations gets the annotations for a given content uuid.
 // If there are draft annotations, they are returned, otherwise the published annotations are returned.
+// With the upp format, the annotations are returned with the predicates they are published with.
+// With includeSuggestions, the suggested annotations for concepts which are not annotated yet are returned as well.
 func (h *Handler) ReadAnnotations(w http.ResponseWriter, r *http.Request) {
 	contentUUID := vestigo.Param(r, "uuid")
 	tID := tidutils.GetTransactionIDFromRequest(r)
@@ -156,27 +326,79 @@ func (h *Handler) ReadAnnotations(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Add("Content-Type", "application/json")
 
-	showHasBrand := false
-	var err error
-	queryParam := r.URL.Query().Get("sendHasBrand")
-	if queryParam != "" {
-		showHasBrand, err = strconv.ParseBool(queryParam)
-		if err != nil {
-			writeMessage(w, fmt.Sprintf("invalid param sendHasBrand: %s ", queryParam), http.StatusBadRequest)
+	showHasBrand, err := sendHasBrandParam(r)
+	if err != nil {
+		writeMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	format, err := formatParam(r)
+	if err != nil {
+		writeMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if format == formatUPP {
+		showHasBrand = true
+	}
+
+	includeSuggestions, err := includeSuggestionsParam(r)
+	if err != nil {
+		writeMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if includeSuggestions && h.suggestionsAPI == nil {
+		writeMessage(w, "Suggestions are not enabled", http.StatusNotImplemented)
+		return
+	}
+
+	query, err := versionQueryParams(r)
+	if err != nil {
+		writeMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var result []annotations.Annotation
+	var hash string
+	hashHeader := annotations.DocumentHashHeader
+	var aug augmentation
+	if query.isSet() {
+		if h.history == nil {
+			writeMessage(w, "Draft annotations history is not enabled", http.StatusNotImplemented)
+			return
+		}
+		result, hash, aug, err = h.readVersion(ctx, contentUUID, query, showHasBrand, readLog)
+		hashHeader = VersionHashHeader
+		if errors.Is(err, history.ErrVersionNotFound) {
+			writeMessage(w, err.Error(), http.StatusNotFound)
 			return
 		}
+	} else {
+		result, hash, aug, err = h.readAnnotations(ctx, contentUUID, showHasBrand, readLog)
 	}
-
-	result, hash, err := h.readAnnotations(ctx, contentUUID, showHasBrand, readLog)
 	if err != nil {
 		handleReadErrors(err, readLog, w)
 		return
 	}
 	if hash != "" {
-		w.Header().Set(annotations.DocumentHashHeader, hash)
+		w.Header().Set(hashHeader, hash)
+	}
+	if includeSuggestions {
+		suggestions, suggestionsDegraded, err := h.readSuggestions(ctx, contentUUID, result, showHasBrand, readLog)
+		if err != nil {
+			handleReadErrors(err, readLog, w)
+			return
+		}
+		result = append(result, suggestions...)
+		aug.degraded = aug.degraded || suggestionsDegraded
+	}
+	if aug.degraded {
+		w.Header().Set(PartiallyAugmentedHeader, "true")
+	}
+	if format == formatUPP {
+		result = switchToPublishedPredicates(result)
 	}
 
-	response := annotations.Annotations{Annotations: result}
+	response := AnnotationsResponse{Annotations: result, Unresolved: aug.unresolved}
 	err = json.NewEncoder(w).Encode(&response)
 	if err != nil {
 		readLog.WithError(err).Error("Failed to encode response")
@@ -190,7 +412,7 @@ func (h *Handler) WriteAnnotations(w http.ResponseWriter, r *http.Request) {
 
 	contentUUID := vestigo.Param(r, "uuid")
 	tID := tidutils.GetTransactionIDFromRequest(r)
-	ctx := tidutils.TransactionAwareContext(context.Background(), tID)
+	ctx := auditContext(tidutils.TransactionAwareContext(context.Background(), tID), r)
 
 	oldHash := r.Header.Get(annotations.PreviousDocumentHashHeader)
 
@@ -232,7 +454,7 @@ func (h *Handler) ReplaceAnnotation(w http.ResponseWriter, r *http.Request) {
 	conceptUUID := vestigo.Param(r, "cuuid")
 
 	tID := tidutils.GetTransactionIDFromRequest(r)
-	ctx := tidutils.TransactionAwareContext(context.Background(), tID)
+	ctx := auditContext(tidutils.TransactionAwareContext(context.Background(), tID), r)
 
 	writeLog := log.WithField(tidutils.TransactionIDKey, tID).WithField("uuid", contentUUID)
 	oldHash := r.Header.Get(annotations.PreviousDocumentHashHeader)
@@ -288,15 +510,8 @@ func (h *Handler) prepareUPPAnnotations(ctx context.Context, contentUUID string,
 		return nil, http.StatusBadRequest, fmt.Errorf("invalid content ID : %w", err)
 	}
 
-	if conceptID != mapper.TransformConceptID(conceptID) {
-		return nil, http.StatusBadRequest, errors.New("invalid concept ID URI")
-	}
-	i := strings.LastIndex(conceptID, "/")
-	if i == -1 || i == len(conceptID)-1 {
-		return nil, http.StatusBadRequest, errors.New("concept ID is empty")
-	}
-	if err := validateUUID(conceptID[i+1:]); err != nil {
-		return nil, http.StatusBadRequest, fmt.Errorf("invalid concept ID : %w", err)
+	if err := validateConceptID(conceptID); err != nil {
+		return nil, http.StatusBadRequest, err
 	}
 
 	ann, err := h.annotationsAPI.GetAllButV2(ctx, contentUUID)
@@ -310,83 +525,203 @@ func (h *Handler) prepareUPPAnnotations(ctx context.Context, contentUUID string,
 	return ann, http.StatusOK, nil
 }
 
-func (h *Handler) saveAndReturnAnnotations(ctx context.Context, uppList []annotations.Annotation, writeLog *log.Entry, oldHash string, contentUUID string) (*annotations.Annotations, string, error) {
+func (h *Handler) saveAndReturnAnnotations(ctx context.Context, uppList []annotations.Annotation, writeLog *log.Entry, oldHash string, contentUUID string) (*AnnotationsResponse, string, error) {
+	return h.saveAndRecordAnnotations(ctx, uppList, writeLog, oldHash, contentUUID, history.Version{Parent: oldHash})
+}
+
+// saveAndRecordAnnotations writes the given annotations and records the new version in the history, if enabled,
+// completing the given version with the new hash and the canonical annotations.
+// The change is recorded in the audit trail and published as an event, if enabled.
+// The annotations whose concept data has not been found are saved as they are and reported as unresolved,
+// unless the strict concepts are enabled.
+func (h *Handler) saveAndRecordAnnotations(ctx context.Context, uppList []annotations.Annotation, writeLog *log.Entry, oldHash string, contentUUID string, version history.Version) (*AnnotationsResponse, string, error) {
 	writeLog.Debug("Move to HasBrand annotations...")
-	uppList, err := h.annotationsAugmenter.AugmentAnnotations(ctx, uppList)
+	augmented, unresolved, err := h.annotationsAugmenter.AugmentAnnotations(ctx, uppList)
 	if err != nil {
 		return nil, "", err
 	}
-	uppList, err = switchToHasBrand(uppList)
-	if err != nil {
-		return nil, "", err
+	if len(unresolved) > 0 {
+		if h.strictConcepts {
+			return nil, "", &unresolvedConceptsError{unresolved: unresolved}
+		}
+		writeLog.WithField("unresolved", len(unresolved)).Warn("Concept data not found for some annotations, saving them as they are")
+		augmented = append(augmented, annotations.UnresolvedAnnotations(uppList, unresolved)...)
 	}
+	uppList = switchToPublishedPredicates(augmented)
 	writeLog.Debug("Canonicalizing annotations...")
 	uppList = h.c14n.Canonicalize(uppList)
+	before := h.readPreviousDraft(ctx, contentUUID, oldHash)
 	writeLog.Debug("Writing to annotations RW...")
 	newAnnotations := &annotations.Annotations{Annotations: uppList}
 	newHash, err := h.annotationsRW.Write(ctx, contentUUID, newAnnotations, oldHash)
+	if errors.Is(err, annotations.ErrConflict) || errors.Is(err, annotations.ErrPreconditionFailed) {
+		writeLog.WithError(err).Warn("Draft annotations have been changed concurrently, merging changes...")
+		status := http.StatusConflict
+		if errors.Is(err, annotations.ErrPreconditionFailed) {
+			status = http.StatusPreconditionFailed
+		}
+		newAnnotations, newHash, before, err = h.mergeAndWrite(ctx, contentUUID, uppList, oldHash, status, writeLog)
+		if err != nil {
+			return nil, "", h.staleHashError(ctx, contentUUID, err)
+		}
+		version.Parent = before.hash
+		uppList = newAnnotations.Annotations
+	}
 	if err != nil {
-		return nil, "", err
+		return nil, "", h.staleHashError(ctx, contentUUID, err)
+	}
+	version.Hash = newHash
+	version.Annotations = uppList
+	h.recordVersion(ctx, contentUUID, version, writeLog)
+	h.recordAudit(ctx, contentUUID, before, uppList, newHash, writeLog)
+	h.publishEvent(ctx, events.Saved, contentUUID, before.hash, newHash, before.changes(uppList), writeLog)
+	return &AnnotationsResponse{Annotations: newAnnotations.Annotations, Unresolved: unresolved}, newHash, nil
+}
+
+// previousDraft holds the canonical draft annotations before a change, to be compared with the saved ones.
+type previousDraft struct {
+	annotations []annotations.Annotation
+	hash        string
+	err         error
+}
+
+// changes returns the canonical diff from the previous draft to the given saved annotations,
+// or nil if the previous draft could not be read.
+func (d previousDraft) changes(saved []annotations.Annotation) *annotations.Diff {
+	if d.err != nil {
+		return nil
 	}
-	return newAnnotations, newHash, nil
+	diff := annotations.Compare(d.annotations, saved)
+	return &diff
 }
 
-func (h *Handler) readAnnotations(ctx context.Context, contentUUID string, showHasBrand bool, readLog *log.Entry) ([]annotations.Annotation, string, error) {
-	var (
-		result        []annotations.Annotation
-		hash          string
-		hasDraft      bool
-		err           error
-		rwAnnotations *annotations.Annotations
-	)
-	readLog.Info("Reading Annotations from Annotations R/W")
-	rwAnnotations, hash, hasDraft, err = h.annotationsRW.Read(ctx, contentUUID)
+// readPreviousDraft returns the draft annotations to be replaced by a write with the given previous hash,
+// when the audit trail, the events or the webhooks are enabled.
+// The version with the given hash is taken from the history if it is there. Otherwise the current draft is read,
+// or the editorially curated published annotations with the predicates they are saved with if there is no draft.
+// If the write is merged with a draft changed concurrently, the draft it has been merged with replaces it.
+func (h *Handler) readPreviousDraft(ctx context.Context, contentUUID string, oldHash string) previousDraft {
+	if h.audit == nil && h.events == nil && h.webhooks == nil {
+		return previousDraft{}
+	}
 
-	if err != nil {
-		return nil, hash, err
+	if h.history != nil && oldHash != "" {
+		if versions, err := h.history.List(ctx, contentUUID); err == nil {
+			if version, err := history.FindByHash(versions, oldHash); err == nil {
+				return previousDraft{annotations: version.Annotations, hash: oldHash}
+			}
+		}
 	}
 
+	draft, hash, hasDraft, err := h.annotationsRW.Read(ctx, contentUUID)
+	if err != nil {
+		return previousDraft{err: err}
+	}
 	if hasDraft {
-		result = rwAnnotations.Annotations
+		return previousDraft{annotations: h.c14n.Canonicalize(draft.Annotations), hash: hash}
+	}
+
+	published, err := h.annotationsAPI.GetAllButV2(ctx, contentUUID)
+	if err != nil {
+		return previousDraft{err: err}
+	}
+	return previousDraft{annotations: h.c14n.Canonicalize(switchToPublishedPredicates(published)), hash: hash}
+}
+
+// readAnnotations returns the augmented draft or published annotations for given content in canonical order,
+// which the indexes of the JSON patches refer to, their hash and how they have been augmented.
+func (h *Handler) readAnnotations(ctx context.Context, contentUUID string, showHasBrand bool, readLog *log.Entry) ([]annotations.Annotation, string, augmentation, error) {
+	result, hash, err := h.fetchAnnotations(ctx, h.readRW, contentUUID, readLog)
+	if err != nil {
+		return nil, hash, augmentation{}, err
+	}
+
+	result, aug, err := h.augmentForRead(ctx, result, showHasBrand, readLog)
+	return canonicalOrder(result), hash, aug, err
+}
+
+// augmentForRead augments the given annotations with recent UPP data and,
+// unless showHasBrand is set, reports hasBrand annotations as isClassifiedBy.
+// It reports the concepts whose data has not been found and, with the degraded reads, whether the annotations
+// have been partially augmented instead of failing.
+func (h *Handler) augmentForRead(ctx context.Context, result []annotations.Annotation, showHasBrand bool, readLog *log.Entry) ([]annotations.Annotation, augmentation, error) {
+	readLog.Info("Augmenting annotations with recent UPP data")
+	var aug augmentation
+	if h.degradedReads {
+		result, aug.unresolved, aug.degraded = h.annotationsAugmenter.AugmentAnnotationsOrDegrade(ctx, result)
+		if aug.degraded {
+			readLog.Warn("Annotations have been partially augmented")
+		}
 	} else {
-		readLog.Info("Annotations not found, retrieving annotations from UPP")
-		result, err = h.annotationsAPI.GetAll(ctx, contentUUID)
+		var err error
+		result, aug.unresolved, err = h.annotationsAugmenter.AugmentAnnotations(ctx, result)
 		if err != nil {
-			return nil, hash, err
+			readLog.WithError(err).Error("Failed to augment annotations")
+			return nil, augmentation{}, err
 		}
 	}
-	readLog.Info("Augmenting annotations with recent UPP data")
-	result, err = h.annotationsAugmenter.AugmentAnnotations(ctx, result)
+
+	if !showHasBrand {
+		result = switchToIsClassifiedBy(result)
+	}
+
+	return result, aug, nil
+}
+
+// canonicalOrder returns a copy of the given annotations sorted by predicate and then by concept ID,
+// the order of the canonicalized annotations.
+func canonicalOrder(list []annotations.Annotation) []annotations.Annotation {
+	sorted := make([]annotations.Annotation, len(list))
+	copy(sorted, list)
+	sort.Stable(annotations.NewCanonicalAnnotationSorter(sorted))
+	return sorted
+}
+
+// fetchAnnotations returns the draft annotations for the given content read with the given RW if there are any,
+// otherwise it falls back to the published annotations. The returned annotations are not augmented.
+func (h *Handler) fetchAnnotations(ctx context.Context, rw annotations.RW, contentUUID string, readLog *log.Entry) ([]annotations.Annotation, string, error) {
+	readLog.Info("Reading Annotations from Annotations R/W")
+	rwAnnotations, hash, hasDraft, err := rw.Read(ctx, contentUUID)
 	if err != nil {
-		readLog.WithError(err).Error("Failed to augment annotations")
 		return nil, hash, err
 	}
 
-	if !showHasBrand {
-		result = switchToIsClassifiedBy(result)
+	if hasDraft {
+		return rwAnnotations.Annotations, hash, nil
 	}
 
-	return result, hash, err
+	readLog.Info("Annotations not found, retrieving annotations from UPP")
+	result, err := h.annotationsAPI.GetAll(ctx, contentUUID)
+	if err != nil {
+		return nil, hash, err
+	}
+	return result, hash, nil
 }
 
 func handleReadErrors(err error, readLog *log.Entry, w http.ResponseWriter) {
 	if isTimeoutErr(err) {
 		readLog.WithError(err).Error("Timeout while reading annotations.")
-		writeMessage(w, "Timeout while reading annotations", http.StatusGatewayTimeout)
+	}
+	var uppErr annotations.UPPError
+	if errors.As(err, &uppErr) && uppErr.UPPBody() != nil {
+		readLog.WithError(err).Error("UPP responded with a client error, forwarding UPP response back to client.")
+		w.WriteHeader(uppErr.Status())
+		w.Write(uppErr.UPPBody())
 		return
 	}
+	status, msg := readErrorStatus(err)
+	writeMessage(w, msg, status)
+}
+
+func readErrorStatus(err error) (int, string) {
+	if isTimeoutErr(err) {
+		return http.StatusGatewayTimeout, "Timeout while reading annotations"
+	}
 	var uppErr annotations.UPPError
 	if errors.As(err, &uppErr) {
-		if uppErr.UPPBody() != nil {
-			readLog.WithError(err).Error("UPP responded with a client error, forwarding UPP response back to client.")
-			w.WriteHeader(uppErr.Status())
-			w.Write(uppErr.UPPBody())
-			return
-		}
-		writeMessage(w, uppErr.Error(), uppErr.Status())
-		return
+		return uppErr.Status(), uppErr.Error()
 	}
-	writeMessage(w, fmt.Sprintf("Failed to read annotations: %v", err), http.StatusInternalServerError)
+	return http.StatusInternalServerError, fmt.Sprintf("Failed to read annotations: %v", err)
 }
 
 func handleWriteErrors(msg string, err error, writeLog *log.Entry, w http.ResponseWriter, httpStatus int) {
@@ -396,8 +731,70 @@ func handleWriteErrors(msg string, err error, writeLog *log.Entry, w http.Respon
 		httpStatus = http.StatusGatewayTimeout
 	}
 
+	var conflictErr *conflictError
+	if errors.As(err, &conflictErr) {
+		writeLog.WithError(err).Warn(msg)
+		writeConflict(w, msg, conflictErr)
+		return
+	}
+
+	var unresolvedErr *unresolvedConceptsError
+	if errors.As(err, &unresolvedErr) {
+		writeLog.WithError(err).Warn(msg)
+		writeUnresolvedConcepts(w, msg, unresolvedErr)
+		return
+	}
+
 	writeLog.WithError(err).Error(msg)
-	writeMessage(w, msg, httpStatus)
+	writeMessage(w, msg, rwErrorStatus(err, httpStatus))
+}
+
+// rwErrorStatus returns the response status for the errors returned by the annotations RW write operations,
+// or the given status for any other error.
+func rwErrorStatus(err error, httpStatus int) int {
+	switch {
+	case errors.Is(err, annotations.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, annotations.ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, annotations.ErrDraftNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, annotations.ErrInvalidDraft):
+		return http.StatusBadRequest
+	case errors.Is(err, annotations.ErrRWUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return httpStatus
+	}
+}
+
+func sendHasBrandParam(r *http.Request) (bool, error) {
+	queryParam := r.URL.Query().Get("sendHasBrand")
+	if queryParam == "" {
+		return false, nil
+	}
+	showHasBrand, err := strconv.ParseBool(queryParam)
+	if err != nil {
+		return false, fmt.Errorf("invalid param sendHasBrand: %s ", queryParam)
+	}
+	return showHasBrand, nil
+}
+
+const (
+	formatPAC = "pac"
+	formatUPP = "upp"
+)
+
+func formatParam(r *http.Request) (string, error) {
+	queryParam := r.URL.Query().Get("format")
+	switch queryParam {
+	case "", formatPAC:
+		return formatPAC, nil
+	case formatUPP:
+		return formatUPP, nil
+	default:
+		return "", fmt.Errorf("invalid param format: %s ", queryParam)
+	}
 }
 
 func readLogEntry(ctx context.Context, contentUUID string) *log.Entry {
@@ -418,6 +815,20 @@ func validateUUID(u string) error {
 	return err
 }
 
+func validateConceptID(conceptID string) error {
+	if conceptID != mapper.TransformConceptID(conceptID) {
+		return errors.New("invalid concept ID URI")
+	}
+	i := strings.LastIndex(conceptID, "/")
+	if i == -1 || i == len(conceptID)-1 {
+		return errors.New("concept ID is empty")
+	}
+	if err := validateUUID(conceptID[i+1:]); err != nil {
+		return fmt.Errorf("invalid concept ID : %w", err)
+	}
+	return nil
+}
+
 func writeMessage(w http.ResponseWriter, msg string, status int) {
 	w.WriteHeader(status)
 
@@ -435,20 +846,19 @@ func writeMessage(w http.ResponseWriter, msg string, status int) {
 	}
 }
 
-func switchToHasBrand(toChange []annotations.Annotation) ([]annotations.Annotation, error) {
+// switchToPublishedPredicates converts the predicates of the given augmented annotations to the ones they are published
+// with, which are also the ones the draft annotations are saved with.
+func switchToPublishedPredicates(toChange []annotations.Annotation) []annotations.Annotation {
 	changed := make([]annotations.Annotation, len(toChange))
 	for idx, ann := range toChange {
 		// We have removed Predicate and Type validation here.
 		// Validating not the user input but the saved annotations can (and did) cause unexpected client errors.
 		// To ensure we have only valid predicates we are adding filtering in the augmenter.
-		if ann.Predicate == mapper.PredicateIsClassifiedBy && ann.Type == mapper.ConceptTypeBrand {
-			ann.Predicate = mapper.PredicateHasBrand
-		}
-
+		ann.Predicate = mapper.PublishedPredicate(ann.Predicate, ann.Type)
 		changed[idx] = ann
 	}
 
-	return changed, nil
+	return changed
 }
 
 func switchToIsClassifiedBy(toChange []annotations.Annotation) []annotations.Annotation {
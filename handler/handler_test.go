@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"testing"
@@ -59,7 +60,7 @@ func TestHappyFetchFromAnnotationsRW(t *testing.T) {
 	err := json.NewDecoder(resp.Body).Decode(&actual)
 	assert.NoError(t, err)
 
-	assert.Equal(t, expectedAnnotations, actual)
+	assert.Equal(t, canonicallySorted(expectedAnnotations), actual)
 	assert.Equal(t, hash, resp.Header.Get(annotations.DocumentHashHeader))
 
 	rw.AssertExpectations(t)
@@ -150,6 +151,124 @@ func TestReadHasBrandAnnotation(t *testing.T) {
 	}
 }
 
+func TestReadAnnotationsUPPFormat(t *testing.T) {
+	augmented := []annotations.Annotation{
+		{
+			Predicate: "http://www.ft.com/ontology/annotation/about",
+			ConceptId: "http://www.ft.com/thing/0a619d71-9af5-3755-90dd-f789b686c67a",
+			Type:      "http://www.ft.com/ontology/person/Person",
+		},
+		{
+			Predicate: "http://www.ft.com/ontology/classification/isClassifiedBy",
+			ConceptId: "http://www.ft.com/thing/87645070-7d8a-492e-9695-bf61ac2b4d18",
+			Type:      "http://www.ft.com/ontology/product/Brand",
+		},
+	}
+	expected := []annotations.Annotation{
+		{
+			Predicate: "http://www.ft.com/ontology/annotation/about",
+			ConceptId: "http://www.ft.com/thing/0a619d71-9af5-3755-90dd-f789b686c67a",
+			Type:      "http://www.ft.com/ontology/person/Person",
+		},
+		{
+			Predicate: "http://www.ft.com/ontology/hasBrand",
+			ConceptId: "http://www.ft.com/thing/87645070-7d8a-492e-9695-bf61ac2b4d18",
+			Type:      "http://www.ft.com/ontology/product/Brand",
+		},
+	}
+
+	hash := randomdata.RandStringRunes(56)
+	rw := &RWMock{}
+	rw.read = func(ctx context.Context, contentUUID string) (*annotations.Annotations, string, bool, error) {
+		return &annotations.Annotations{Annotations: augmented}, hash, true, nil
+	}
+	aug := &AugmenterMock{
+		augment: func(ctx context.Context, depletedAnnotations []annotations.Annotation) ([]annotations.Annotation, error) {
+			return augmented, nil
+		},
+	}
+	h := handler.New(rw, &AnnotationsAPIMock{}, nil, aug, time.Second)
+	r := vestigo.NewRouter()
+	r.Get("/drafts/content/:uuid/annotations", h.ReadAnnotations)
+
+	req := httptest.NewRequest("GET", "/drafts/content/83a201c6-60cd-11e7-91a7-502f7ee26895/annotations?format=upp", nil)
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	actual := annotations.Annotations{}
+	err := json.NewDecoder(resp.Body).Decode(&actual)
+	assert.NoError(t, err)
+
+	assert.Equal(t, annotations.Annotations{Annotations: expected}, actual)
+	assert.Equal(t, hash, resp.Header.Get(annotations.DocumentHashHeader))
+}
+
+func TestReadAnnotationsInvalidFormat(t *testing.T) {
+	h := handler.New(&RWMock{}, &AnnotationsAPIMock{}, nil, &AugmenterMock{}, time.Second)
+	r := vestigo.NewRouter()
+	r.Get("/drafts/content/:uuid/annotations", h.ReadAnnotations)
+
+	req := httptest.NewRequest("GET", "/drafts/content/83a201c6-60cd-11e7-91a7-502f7ee26895/annotations?format=xml", nil)
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestWriteAnnotationsKeepsLifecycleAndProvenance(t *testing.T) {
+	written := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{
+			Predicate:  "http://www.ft.com/ontology/annotation/about",
+			ConceptId:  "http://www.ft.com/thing/0a619d71-9af5-3755-90dd-f789b686c67a",
+			Lifecycle:  "v2",
+			Provenance: "machine",
+		},
+	}}
+
+	rw := new(RWMock)
+	rw.On("Read", mock.Anything, "83a201c6-60cd-11e7-91a7-502f7ee26895").Return(nil, "", false, nil).Maybe()
+	rw.On("Write", mock.Anything, "83a201c6-60cd-11e7-91a7-502f7ee26895", written, "old-hash").Return("new-hash", nil).Once()
+	aug := &AugmenterMock{
+		augment: func(ctx context.Context, depletedAnnotations []annotations.Annotation) ([]annotations.Annotation, error) {
+			return depletedAnnotations, nil
+		},
+	}
+	h := handler.New(rw, new(AnnotationsAPIMock), annotations.NewCanonicalizer(annotations.NewCanonicalAnnotationSorter), aug, time.Second)
+	r := vestigo.NewRouter()
+	r.Put("/drafts/content/:uuid/annotations", h.WriteAnnotations)
+
+	req := httptest.NewRequest("PUT", "/drafts/content/83a201c6-60cd-11e7-91a7-502f7ee26895/annotations", strings.NewReader(`{"annotations":[{
+		"predicate": "http://www.ft.com/ontology/annotation/about",
+		"id": "http://www.ft.com/thing/0a619d71-9af5-3755-90dd-f789b686c67a",
+		"prefLabel": "Some concept",
+		"lifecycle": "v2",
+		"provenance": "machine"
+	}]}`))
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	req.Header.Set(annotations.PreviousDocumentHashHeader, "old-hash")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	actual := annotations.Annotations{}
+	err := json.NewDecoder(resp.Body).Decode(&actual)
+	assert.NoError(t, err)
+	assert.Equal(t, *written, actual)
+
+	rw.AssertExpectations(t)
+}
+
 func TestAddAnnotation(t *testing.T) {
 	rw := &RWMock{}
 	annAPI := &AnnotationsAPIMock{}
@@ -212,6 +331,34 @@ func TestAddAnnotation(t *testing.T) {
 			},
 			requestStatusCode: http.StatusOK,
 		},
+		"success - keep the scores of the added annotation": {
+			augmented: []annotations.Annotation{
+				{
+					Predicate:       "http://www.ft.com/ontology/annotation/about",
+					ConceptId:       "http://www.ft.com/thing/0a619d71-9af5-3755-90dd-f789b686c67a",
+					ApiUrl:          "http://api.ft.com/concepts/0a619d71-9af5-3755-90dd-f789b686c67a",
+					Type:            "http://www.ft.com/ontology/person/Person",
+					PrefLabel:       "Barack H. Obama",
+					RelevanceScore:  score(0.8),
+					ConfidenceScore: score(0.95),
+				},
+			},
+			saved: []annotations.Annotation{
+				{
+					Predicate:       "http://www.ft.com/ontology/annotation/about",
+					ConceptId:       "http://www.ft.com/thing/0a619d71-9af5-3755-90dd-f789b686c67a",
+					RelevanceScore:  score(0.8),
+					ConfidenceScore: score(0.95),
+				},
+			},
+			added: annotations.Annotation{
+				Predicate:       "http://www.ft.com/ontology/annotation/about",
+				ConceptId:       "http://www.ft.com/thing/0a619d71-9af5-3755-90dd-f789b686c67a",
+				RelevanceScore:  score(0.8),
+				ConfidenceScore: score(0.95),
+			},
+			requestStatusCode: http.StatusOK,
+		},
 	}
 
 	for name, test := range tests {
@@ -548,6 +695,76 @@ func TestUnHappyAugmenter(t *testing.T) {
 	annAPI.AssertExpectations(t)
 }
 
+func TestReadAnnotationsDegraded(t *testing.T) {
+	tests := map[string]struct {
+		degraded       bool
+		expectedHeader string
+	}{
+		"partially augmented": {degraded: true, expectedHeader: "true"},
+		"fully augmented":     {degraded: false, expectedHeader: ""},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rw := new(RWMock)
+			rw.On("Read", mock.Anything, "83a201c6-60cd-11e7-91a7-502f7ee26895").Return(&expectedAnnotations, "a-hash", true, nil)
+			aug := new(AugmenterMock)
+			aug.On("AugmentAnnotationsOrDegrade", mock.Anything, expectedAnnotations.Annotations).Return(expectedAnnotations.Annotations, test.degraded)
+
+			h := handler.New(rw, new(AnnotationsAPIMock), nil, aug, time.Second, handler.WithDegradedReads())
+			r := vestigo.NewRouter()
+			r.Get("/drafts/content/:uuid/annotations", h.ReadAnnotations)
+
+			req := httptest.NewRequest("GET", "http://api.ft.com/drafts/content/83a201c6-60cd-11e7-91a7-502f7ee26895/annotations", nil)
+			req.Header.Set(tidutils.TransactionIDHeader, testTID)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+			resp := w.Result()
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, "a-hash", resp.Header.Get(annotations.DocumentHashHeader))
+			assert.Equal(t, test.expectedHeader, resp.Header.Get(handler.PartiallyAugmentedHeader))
+
+			actual := annotations.Annotations{}
+			err := json.NewDecoder(resp.Body).Decode(&actual)
+			assert.NoError(t, err)
+			assert.Equal(t, canonicallySorted(expectedAnnotations), actual)
+
+			rw.AssertExpectations(t)
+			aug.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWriteAnnotationsStrictWithDegradedReads(t *testing.T) {
+	rw := new(RWMock)
+	aug := new(AugmenterMock)
+	aug.On("AugmentAnnotations", mock.Anything, mock.Anything).Return([]annotations.Annotation{}, errors.New("computer says no"))
+
+	canonicalizer := annotations.NewCanonicalizer(annotations.NewCanonicalAnnotationSorter)
+	h := handler.New(rw, new(AnnotationsAPIMock), canonicalizer, aug, time.Second, handler.WithDegradedReads())
+	r := vestigo.NewRouter()
+	r.Put("/drafts/content/:uuid/annotations", h.WriteAnnotations)
+
+	entity := bytes.Buffer{}
+	err := json.NewEncoder(&entity).Encode(&expectedAnnotations)
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest("PUT", "http://api.ft.com/drafts/content/83a201c6-60cd-11e7-91a7-502f7ee26895/annotations", &entity)
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get(handler.PartiallyAugmentedHeader))
+
+	rw.AssertNotCalled(t, "Write", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	aug.AssertNotCalled(t, "AugmentAnnotationsOrDegrade", mock.Anything, mock.Anything)
+}
+
 func TestFetchFromAnnotationsAPIIfNotFoundInRW(t *testing.T) {
 	aug := new(AugmenterMock)
 	aug.On("AugmentAnnotations", mock.Anything, expectedAnnotations.Annotations).Return(expectedAnnotations.Annotations, nil)
@@ -578,7 +795,7 @@ func TestFetchFromAnnotationsAPIIfNotFoundInRW(t *testing.T) {
 	err := json.NewDecoder(resp.Body).Decode(&actual)
 	assert.NoError(t, err)
 
-	assert.Equal(t, expectedAnnotations, actual)
+	assert.Equal(t, canonicallySorted(expectedAnnotations), actual)
 	assert.Empty(t, resp.Header.Get(annotations.DocumentHashHeader))
 
 	rw.AssertExpectations(t)
@@ -1575,6 +1792,159 @@ func TestUnHappyDeleteAnnotationsWhenWritingAnnotationsFails(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
 }
 
+func TestSaveAnnotationsRWErrors(t *testing.T) {
+	tests := map[string]struct {
+		writeErr       error
+		expectedStatus int
+	}{
+		"precondition failed": {
+			writeErr:       annotations.NewRWError(http.StatusPreconditionFailed, "current-hash", annotations.ErrPreconditionFailed),
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		"not found": {
+			writeErr:       annotations.NewRWError(http.StatusNotFound, "", annotations.ErrDraftNotFound),
+			expectedStatus: http.StatusNotFound,
+		},
+		"bad request": {
+			writeErr:       annotations.NewRWError(http.StatusUnprocessableEntity, "", annotations.ErrInvalidDraft),
+			expectedStatus: http.StatusBadRequest,
+		},
+		"unavailable": {
+			writeErr:       annotations.NewRWError(http.StatusServiceUnavailable, "", annotations.ErrRWUnavailable),
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		"unexpected status": {
+			writeErr:       annotations.NewRWError(http.StatusTeapot, "", annotations.ErrUnexpectedStatusWrite),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rw := new(RWMock)
+			rw.On("Write", mock.Anything, "83a201c6-60cd-11e7-91a7-502f7ee26895", mock.Anything, "old-hash").Return("", test.writeErr)
+			// a precondition failure is merged, which fails without history
+			rw.On("Read", mock.Anything, "83a201c6-60cd-11e7-91a7-502f7ee26895").Return(&annotations.Annotations{}, "current-hash", true, nil).Maybe()
+			aug := &AugmenterMock{
+				augment: func(ctx context.Context, depletedAnnotations []annotations.Annotation) ([]annotations.Annotation, error) {
+					return depletedAnnotations, nil
+				},
+			}
+
+			h := handler.New(rw, new(AnnotationsAPIMock), annotations.NewCanonicalizer(annotations.NewCanonicalAnnotationSorter), aug, time.Second)
+			r := vestigo.NewRouter()
+			r.Put("/drafts/content/:uuid/annotations", h.WriteAnnotations)
+
+			req := httptest.NewRequest(
+				"PUT",
+				"http://api.ft.com/drafts/content/83a201c6-60cd-11e7-91a7-502f7ee26895/annotations",
+				strings.NewReader(`{"annotations":[{"predicate":"http://www.ft.com/ontology/annotation/about","id":"http://www.ft.com/thing/0a619d71-9af5-3755-90dd-f789b686c67a"}]}`))
+			req.Header.Set(tidutils.TransactionIDHeader, testTID)
+			req.Header.Set(annotations.PreviousDocumentHashHeader, "old-hash")
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+			resp := w.Result()
+			assert.Equal(t, test.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestDiscardDraftAnnotationsStaleHash(t *testing.T) {
+	rw := new(RWMock)
+	rw.On("Delete", mock.Anything, "83a201c6-60cd-11e7-91a7-502f7ee26895", "old-hash").
+		Return(annotations.NewRWError(http.StatusPreconditionFailed, "", annotations.ErrPreconditionFailed))
+	rw.On("Read", mock.Anything, "83a201c6-60cd-11e7-91a7-502f7ee26895").Return(&expectedAnnotations, "current-hash", true, nil)
+
+	h := handler.New(rw, new(AnnotationsAPIMock), nil, new(AugmenterMock), time.Second)
+	r := vestigo.NewRouter()
+	r.Delete("/drafts/content/:uuid/annotations", h.DiscardDraftAnnotations)
+
+	req := httptest.NewRequest("DELETE", "http://api.ft.com/drafts/content/83a201c6-60cd-11e7-91a7-502f7ee26895/annotations", nil)
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	req.Header.Set(annotations.PreviousDocumentHashHeader, "old-hash")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+	resp := w.Result()
+	assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+	assert.Equal(t, "current-hash", resp.Header.Get(annotations.DocumentHashHeader))
+
+	actual := handler.ConflictResponse{}
+	err := json.NewDecoder(resp.Body).Decode(&actual)
+	assert.NoError(t, err)
+	assert.Equal(t, "current-hash", actual.CurrentHash)
+
+	rw.AssertExpectations(t)
+}
+
+func TestHappyDiscardDraftAnnotations(t *testing.T) {
+	rw := new(RWMock)
+	oldHash := randomdata.RandStringRunes(56)
+	rw.On("Delete", mock.AnythingOfType("*context.valueCtx"), "83a201c6-60cd-11e7-91a7-502f7ee26895", oldHash).Return(nil)
+	annAPI := new(AnnotationsAPIMock)
+	aug := new(AugmenterMock)
+
+	h := handler.New(rw, annAPI, nil, aug, time.Second)
+	r := vestigo.NewRouter()
+	r.Delete("/drafts/content/:uuid/annotations", h.DiscardDraftAnnotations)
+
+	req := httptest.NewRequest("DELETE", "http://api.ft.com/drafts/content/83a201c6-60cd-11e7-91a7-502f7ee26895/annotations", nil)
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	req.Header.Set(annotations.PreviousDocumentHashHeader, oldHash)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+	resp := w.Result()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	rw.AssertExpectations(t)
+	annAPI.AssertExpectations(t)
+	aug.AssertExpectations(t)
+}
+
+func TestUnHappyDiscardDraftAnnotations(t *testing.T) {
+	tests := map[string]struct {
+		contentUUID    string
+		deleteErr      error
+		expectedStatus int
+	}{
+		"invalid content UUID": {
+			contentUUID:    "foo",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"draft not found": {
+			contentUUID:    "83a201c6-60cd-11e7-91a7-502f7ee26895",
+			deleteErr:      annotations.ErrDraftNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		"RW error": {
+			contentUUID:    "83a201c6-60cd-11e7-91a7-502f7ee26895",
+			deleteErr:      fmt.Errorf("status 503: %w", annotations.ErrUnexpectedStatusDelete),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rw := new(RWMock)
+			rw.On("Delete", mock.Anything, test.contentUUID, "").Return(test.deleteErr)
+
+			h := handler.New(rw, new(AnnotationsAPIMock), nil, new(AugmenterMock), time.Second)
+			r := vestigo.NewRouter()
+			r.Delete("/drafts/content/:uuid/annotations", h.DiscardDraftAnnotations)
+
+			req := httptest.NewRequest("DELETE", "http://api.ft.com/drafts/content/"+test.contentUUID+"/annotations", nil)
+			req.Header.Set(tidutils.TransactionIDHeader, testTID)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+			resp := w.Result()
+			assert.Equal(t, test.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
 func TestHappyAddAnnotation(t *testing.T) {
 	rw := new(RWMock)
 	annAPI := new(AnnotationsAPIMock)
@@ -2375,21 +2745,75 @@ func TestUnhappyReplaceAnnotationWhenNoAnnotationsFound(t *testing.T) {
 
 type AugmenterMock struct {
 	mock.Mock
-	augment func(ctx context.Context, depletedAnnotations []annotations.Annotation) ([]annotations.Annotation, error)
+	augment               func(ctx context.Context, depletedAnnotations []annotations.Annotation) ([]annotations.Annotation, error)
+	augmentBatch          func(ctx context.Context, depletedAnnotations map[string][]annotations.Annotation) (map[string][]annotations.Annotation, error)
+	augmentOrDegrade      func(ctx context.Context, depletedAnnotations []annotations.Annotation) ([]annotations.Annotation, bool)
+	augmentBatchOrDegrade func(ctx context.Context, depletedAnnotations map[string][]annotations.Annotation) (map[string][]annotations.Annotation, bool)
+	unresolved            []annotations.UnresolvedConcept
+	unresolvedBatch       map[string][]annotations.UnresolvedConcept
 }
 
-func (m *AugmenterMock) AugmentAnnotations(ctx context.Context, depletedAnnotations []annotations.Annotation) ([]annotations.Annotation, error) {
+func (m *AugmenterMock) AugmentAnnotations(ctx context.Context, depletedAnnotations []annotations.Annotation) ([]annotations.Annotation, []annotations.UnresolvedConcept, error) {
 	if m.augment != nil {
-		return m.augment(ctx, depletedAnnotations)
+		augmented, err := m.augment(ctx, depletedAnnotations)
+		return augmented, m.unresolved, err
 	}
 	args := m.Called(ctx, depletedAnnotations)
-	return args.Get(0).([]annotations.Annotation), args.Error(1)
+	return args.Get(0).([]annotations.Annotation), m.unresolved, args.Error(1)
+}
+
+func (m *AugmenterMock) AugmentAnnotationsOrDegrade(ctx context.Context, depletedAnnotations []annotations.Annotation) ([]annotations.Annotation, []annotations.UnresolvedConcept, bool) {
+	if m.augmentOrDegrade != nil {
+		augmented, degraded := m.augmentOrDegrade(ctx, depletedAnnotations)
+		return augmented, m.unresolved, degraded
+	}
+	args := m.Called(ctx, depletedAnnotations)
+	return args.Get(0).([]annotations.Annotation), m.unresolved, args.Bool(1)
+}
+
+func (m *AugmenterMock) AugmentAnnotationsBatch(ctx context.Context, depletedAnnotations map[string][]annotations.Annotation) (map[string][]annotations.Annotation, map[string][]annotations.UnresolvedConcept, error) {
+	if m.augmentBatch != nil {
+		augmented, err := m.augmentBatch(ctx, depletedAnnotations)
+		return augmented, m.unresolvedBatch, err
+	}
+	args := m.Called(ctx, depletedAnnotations)
+	var res map[string][]annotations.Annotation
+	if v := args.Get(0); v != nil {
+		res = v.(map[string][]annotations.Annotation)
+	}
+	return res, m.unresolvedBatch, args.Error(1)
+}
+
+func (m *AugmenterMock) AugmentAnnotationsBatchOrDegrade(ctx context.Context, depletedAnnotations map[string][]annotations.Annotation) (map[string][]annotations.Annotation, map[string][]annotations.UnresolvedConcept, bool) {
+	if m.augmentBatchOrDegrade != nil {
+		augmented, degraded := m.augmentBatchOrDegrade(ctx, depletedAnnotations)
+		return augmented, m.unresolvedBatch, degraded
+	}
+	args := m.Called(ctx, depletedAnnotations)
+	var res map[string][]annotations.Annotation
+	if v := args.Get(0); v != nil {
+		res = v.(map[string][]annotations.Annotation)
+	}
+	return res, m.unresolvedBatch, args.Bool(1)
+}
+
+func score(s float64) *float64 {
+	return &s
+}
+
+// canonicallySorted returns a copy of the given annotations in canonical order, the order of the read responses.
+func canonicallySorted(in annotations.Annotations) annotations.Annotations {
+	sorted := make([]annotations.Annotation, len(in.Annotations))
+	copy(sorted, in.Annotations)
+	sort.Sort(annotations.NewCanonicalAnnotationSorter(sorted))
+	return annotations.Annotations{Annotations: sorted}
 }
 
 type RWMock struct {
 	mock.Mock
 	read     func(ctx context.Context, contentUUID string) (*annotations.Annotations, string, bool, error)
 	write    func(ctx context.Context, contentUUID string, a *annotations.Annotations, hash string) (string, error)
+	delete   func(ctx context.Context, contentUUID string, hash string) error
 	endpoint func() string
 	gtg      func() error
 }
@@ -2417,6 +2841,14 @@ func (m *RWMock) Write(ctx context.Context, contentUUID string, a *annotations.A
 	return args.String(0), args.Error(1)
 }
 
+func (m *RWMock) Delete(ctx context.Context, contentUUID string, hash string) error {
+	if m.delete != nil {
+		return m.delete(ctx, contentUUID, hash)
+	}
+	args := m.Called(ctx, contentUUID, hash)
+	return args.Error(0)
+}
+
 func (m *RWMock) Endpoint() string {
 	if m.endpoint != nil {
 		return m.endpoint()
@@ -2437,6 +2869,7 @@ type AnnotationsAPIMock struct {
 	mock.Mock
 	getAll      func(ctx context.Context, contentUUID string) ([]annotations.Annotation, error)
 	getAllButV2 func(ctx context.Context, contentUUID string) ([]annotations.Annotation, error)
+	getImplicit func(ctx context.Context, contentUUID string) ([]annotations.Annotation, error)
 	endpoint    func() string
 	gtg         func() error
 }
@@ -2457,6 +2890,14 @@ func (m *AnnotationsAPIMock) GetAllButV2(ctx context.Context, contentUUID string
 	return args.Get(0).([]annotations.Annotation), args.Error(1)
 }
 
+func (m *AnnotationsAPIMock) GetImplicit(ctx context.Context, contentUUID string) ([]annotations.Annotation, error) {
+	if m.getImplicit != nil {
+		return m.getImplicit(ctx, contentUUID)
+	}
+	args := m.Called(ctx, contentUUID)
+	return args.Get(0).([]annotations.Annotation), args.Error(1)
+}
+
 func (m *AnnotationsAPIMock) Endpoint() string {
 	if m.endpoint != nil {
 		return m.endpoint()
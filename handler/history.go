@@ -0,0 +1,279 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Financial-Times/draft-annotations-api/annotations"
+	"github.com/Financial-Times/draft-annotations-api/history"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/husobee/vestigo"
+	log "github.com/sirupsen/logrus"
+)
+
+// VersionsResponse is the body of the versions endpoint response.
+type VersionsResponse struct {
+	Versions []history.Version `json:"versions"`
+}
+
+// versionQuery selects a saved version of the draft annotations, either by hash or by time.
+type versionQuery struct {
+	hash string
+	asOf time.Time
+}
+
+func (q versionQuery) isSet() bool {
+	return q.hash != "" || !q.asOf.IsZero()
+}
+
+// ListVersions returns the saved versions of the draft annotations for a given content uuid, oldest first.
+// The annotations of each version can be read with the version query parameter of the read endpoint.
+func (h *Handler) ListVersions(w http.ResponseWriter, r *http.Request) {
+	contentUUID := vestigo.Param(r, "uuid")
+	tID := tidutils.GetTransactionIDFromRequest(r)
+
+	ctx, cancel := context.WithTimeout(tidutils.TransactionAwareContext(r.Context(), tID), h.timeout)
+	defer cancel()
+
+	readLog := readLogEntry(ctx, contentUUID)
+
+	w.Header().Add("Content-Type", "application/json")
+
+	if err := validateUUID(contentUUID); err != nil {
+		writeMessage(w, "Invalid content UUID: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if h.history == nil {
+		writeMessage(w, "Draft annotations history is not enabled", http.StatusNotImplemented)
+		return
+	}
+
+	versions, err := h.history.List(ctx, contentUUID)
+	if err != nil {
+		readLog.WithError(err).Error("Failed to read draft annotations history")
+		writeMessage(w, fmt.Sprintf("Failed to read draft annotations history: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	response := VersionsResponse{Versions: make([]history.Version, 0, len(versions))}
+	for _, v := range versions {
+		v.Annotations = nil
+		response.Versions = append(response.Versions, v)
+	}
+
+	err = json.NewEncoder(w).Encode(&response)
+	if err != nil {
+		readLog.WithError(err).Error("Failed to encode response")
+		handleReadErrors(err, readLog, w)
+	}
+}
+
+// readVersion returns the augmented annotations of the saved version selected by the given query in canonical order,
+// its hash and how the annotations have been augmented.
+func (h *Handler) readVersion(ctx context.Context, contentUUID string, query versionQuery, showHasBrand bool, readLog *log.Entry) ([]annotations.Annotation, string, augmentation, error) {
+	readLog.Info("Reading Annotations from draft history")
+	versions, err := h.history.List(ctx, contentUUID)
+	if err != nil {
+		return nil, "", augmentation{}, err
+	}
+
+	var version history.Version
+	if query.hash != "" {
+		version, err = history.FindByHash(versions, query.hash)
+	} else {
+		version, err = history.FindAsOf(versions, query.asOf)
+	}
+	if err != nil {
+		return nil, "", augmentation{}, err
+	}
+
+	result, aug, err := h.augmentForRead(ctx, version.Annotations, showHasBrand, readLog)
+	return canonicalOrder(result), version.Hash, aug, err
+}
+
+// UndoAnnotations writes again the draft annotations as they were before the current version was saved.
+// The current version is the one with the Previous-Document-Hash of the request, or with the hash of the current draft
+// if it is not set, or the most recent one if there is no draft.
+// Undoing several times goes back through the recorded versions until the oldest one is reached.
+func (h *Handler) UndoAnnotations(w http.ResponseWriter, r *http.Request) {
+	h.restoreAnnotations(w, r, func(versions []history.Version, oldHash string) (history.Version, history.Version, error) {
+		current, i, err := currentVersion(versions, oldHash)
+		if err != nil {
+			return history.Version{}, history.Version{}, err
+		}
+		if current.Parent == "" {
+			return history.Version{}, history.Version{}, fmt.Errorf("nothing to undo: %w", history.ErrVersionNotFound)
+		}
+		// the parent is searched among the older versions only, because a version writing the same annotations
+		// as an older one, e.g. a restore, has the same hash as it
+		target, err := history.FindByHash(versions[:i], current.Parent)
+		if err != nil {
+			return history.Version{}, history.Version{}, fmt.Errorf("nothing to undo: %w", err)
+		}
+		// the new version takes the place of the target in the history, so that undoing it goes further back
+		return target, history.Version{Parent: target.Parent, RestoredFrom: target.Hash}, nil
+	})
+}
+
+// RestoreAnnotations writes again the draft annotations of the version with the hash given as query parameter.
+// The restore is itself a new version, which can be undone.
+func (h *Handler) RestoreAnnotations(w http.ResponseWriter, r *http.Request) {
+	hash := r.URL.Query().Get("hash")
+	h.restoreAnnotations(w, r, func(versions []history.Version, oldHash string) (history.Version, history.Version, error) {
+		if hash == "" {
+			return history.Version{}, history.Version{}, errMissingRestoreHash
+		}
+		target, err := history.FindByHash(versions, hash)
+		if err != nil {
+			return history.Version{}, history.Version{}, err
+		}
+		return target, history.Version{Parent: oldHash, RestoredFrom: target.Hash}, nil
+	})
+}
+
+var errMissingRestoreHash = errors.New("the hash param of the version to restore is required")
+
+// selectVersion returns the version to restore from the recorded ones and the template of the version to record.
+type selectVersion func(versions []history.Version, oldHash string) (history.Version, history.Version, error)
+
+// restoreAnnotations re-augments the annotations of the selected version, dropping the concepts that no longer exist,
+// and writes them as a new version with the usual optimistic hash check. Without Previous-Document-Hash,
+// the hash of the current draft is read first and checked instead, so that a concurrent change is not overwritten.
+func (h *Handler) restoreAnnotations(w http.ResponseWriter, r *http.Request, selectVersion selectVersion) {
+	w.Header().Add("Content-Type", "application/json")
+
+	contentUUID := vestigo.Param(r, "uuid")
+
+	tID := tidutils.GetTransactionIDFromRequest(r)
+	ctx := auditContext(tidutils.TransactionAwareContext(context.Background(), tID), r)
+	writeLog := log.WithField(tidutils.TransactionIDKey, tID).WithField("uuid", contentUUID)
+
+	oldHash := r.Header.Get(annotations.PreviousDocumentHashHeader)
+
+	if err := validateUUID(contentUUID); err != nil {
+		handleWriteErrors("Invalid content UUID", err, writeLog, w, http.StatusBadRequest)
+		return
+	}
+	if h.history == nil {
+		writeMessage(w, "Draft annotations history is not enabled", http.StatusNotImplemented)
+		return
+	}
+
+	if oldHash == "" {
+		_, currentHash, _, err := h.annotationsRW.Read(ctx, contentUUID)
+		if err != nil {
+			handleWriteErrors("Error reading current draft annotations", err, writeLog, w, http.StatusInternalServerError)
+			return
+		}
+		oldHash = currentHash
+	}
+
+	versions, err := h.history.List(ctx, contentUUID)
+	if err != nil {
+		handleWriteErrors("Error reading draft annotations history", err, writeLog, w, http.StatusInternalServerError)
+		return
+	}
+
+	target, version, err := selectVersion(versions, oldHash)
+	if errors.Is(err, errMissingRestoreHash) {
+		handleWriteErrors("Invalid request", err, writeLog, w, http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		handleWriteErrors("Error selecting the version to restore", err, writeLog, w, http.StatusNotFound)
+		return
+	}
+
+	restored, err := h.withoutUnresolvedConcepts(ctx, target.Annotations, writeLog)
+	if err != nil {
+		handleWriteErrors("Error augmenting the draft annotations version", err, writeLog, w, http.StatusInternalServerError)
+		return
+	}
+
+	writeLog.WithField("version", target.Hash).Info("Restoring draft annotations version")
+	savedAnnotations, newHash, err := h.saveAndRecordAnnotations(ctx, restored, writeLog, oldHash, contentUUID, version)
+	if err != nil {
+		handleWriteErrors("Error writing draft annotations", err, writeLog, w, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set(annotations.DocumentHashHeader, newHash)
+
+	err = json.NewEncoder(w).Encode(savedAnnotations)
+	if err != nil {
+		handleWriteErrors("Error in encoding draft annotations response", err, writeLog, w, http.StatusInternalServerError)
+		return
+	}
+}
+
+// withoutUnresolvedConcepts returns the given annotations without the ones whose concept data is not found anymore.
+// They are dropped explicitly, since the writes would otherwise save them as they are, or reject them in strict mode.
+func (h *Handler) withoutUnresolvedConcepts(ctx context.Context, list []annotations.Annotation, writeLog *log.Entry) ([]annotations.Annotation, error) {
+	_, unresolved, err := h.annotationsAugmenter.AugmentAnnotations(ctx, list)
+	if err != nil || len(unresolved) == 0 {
+		return list, err
+	}
+	writeLog.WithField("unresolved", len(unresolved)).Info("Dropping the concepts which no longer exist from the restored version")
+
+	dropped := make(map[string]struct{}, len(unresolved))
+	for _, u := range unresolved {
+		dropped[u.ConceptId] = struct{}{}
+	}
+	result := make([]annotations.Annotation, 0, len(list))
+	for _, ann := range list {
+		if _, found := dropped[ann.ConceptId]; !found {
+			result = append(result, ann)
+		}
+	}
+	return result, nil
+}
+
+// currentVersion returns the version with the given hash, or the most recent version if the hash is empty,
+// and its index in the given versions.
+func currentVersion(versions []history.Version, hash string) (history.Version, int, error) {
+	for i := len(versions) - 1; i >= 0; i-- {
+		if hash == "" || versions[i].Hash == hash {
+			return versions[i], i, nil
+		}
+	}
+	return history.Version{}, 0, history.ErrVersionNotFound
+}
+
+// recordVersion saves the given version of the draft annotations in the history, if enabled.
+// A failure is only logged: the draft is saved, but it cannot be undone to, restored or used as a merge base.
+func (h *Handler) recordVersion(ctx context.Context, contentUUID string, version history.Version, writeLog *log.Entry) {
+	if h.history == nil {
+		return
+	}
+
+	version.TransactionID, _ = tidutils.GetTransactionIDFromContext(ctx)
+	version.Timestamp = time.Now().UTC()
+	if err := h.history.Append(ctx, contentUUID, version); err != nil {
+		writeLog.WithError(err).Warn("Failed to record draft annotations version in history")
+	}
+}
+
+func versionQueryParams(r *http.Request) (versionQuery, error) {
+	var query versionQuery
+	params := r.URL.Query()
+
+	query.hash = params.Get("version")
+	asOf := params.Get("asOf")
+	if asOf == "" {
+		return query, nil
+	}
+	if query.hash != "" {
+		return versionQuery{}, errors.New("only one of the version and asOf params can be provided")
+	}
+
+	t, err := time.Parse(time.RFC3339, asOf)
+	if err != nil {
+		return versionQuery{}, fmt.Errorf("invalid param asOf: %s ", asOf)
+	}
+	query.asOf = t
+	return query, nil
+}
@@ -0,0 +1,507 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Financial-Times/draft-annotations-api/annotations"
+	"github.com/Financial-Times/draft-annotations-api/handler"
+	"github.com/Financial-Times/draft-annotations-api/history"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/husobee/vestigo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newHistoryRouter(rw *RWMock, store history.Store) *vestigo.Router {
+	aug := &AugmenterMock{
+		augment: func(ctx context.Context, depletedAnnotations []annotations.Annotation) ([]annotations.Annotation, error) {
+			return depletedAnnotations, nil
+		},
+	}
+	var opts []handler.Option
+	if store != nil {
+		opts = append(opts, handler.WithHistory(store))
+	}
+	h := handler.New(rw, new(AnnotationsAPIMock), annotations.NewCanonicalizer(annotations.NewCanonicalAnnotationSorter), aug, time.Second, opts...)
+	r := vestigo.NewRouter()
+	r.Get("/drafts/content/:uuid/annotations", h.ReadAnnotations)
+	r.Put("/drafts/content/:uuid/annotations", h.WriteAnnotations)
+	r.Get("/drafts/content/:uuid/annotations/versions", h.ListVersions)
+	return r
+}
+
+func TestWriteAnnotationsRecordsVersion(t *testing.T) {
+	store := history.NewMemoryStore(0)
+	expected := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+		{Predicate: patchMentions, ConceptId: patchConceptB},
+	}}
+
+	rw := new(RWMock)
+	rw.On("Write", mock.Anything, patchContentUUID, expected, "old-hash").Return("new-hash", nil)
+
+	r := newHistoryRouter(rw, store)
+
+	req := httptest.NewRequest("PUT", "/drafts/content/"+patchContentUUID+"/annotations", strings.NewReader(`{"annotations":[
+		{"predicate":"`+patchMentions+`","id":"`+patchConceptB+`"},
+		{"predicate":"`+patchAbout+`","id":"`+patchConceptA+`"}
+	]}`))
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	req.Header.Set(annotations.PreviousDocumentHashHeader, "old-hash")
+	w := httptest.NewRecorder()
+
+	before := time.Now()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+	versions, err := store.List(context.Background(), patchContentUUID)
+	assert.NoError(t, err)
+	assert.Len(t, versions, 1)
+	assert.Equal(t, "new-hash", versions[0].Hash)
+	assert.Equal(t, testTID, versions[0].TransactionID)
+	assert.Equal(t, expected.Annotations, versions[0].Annotations)
+	assert.False(t, versions[0].Timestamp.Before(before.Truncate(time.Second)))
+
+	rw.AssertExpectations(t)
+}
+
+func newTestHistory(t *testing.T) history.Store {
+	store := history.NewMemoryStore(0)
+	versions := []history.Version{
+		{
+			Hash:          "hash-1",
+			Timestamp:     time.Date(2023, time.March, 1, 10, 0, 0, 0, time.UTC),
+			TransactionID: "tid_1",
+			Annotations:   []annotations.Annotation{{Predicate: patchAbout, ConceptId: patchConceptA}},
+		},
+		{
+			Hash:          "hash-2",
+			Timestamp:     time.Date(2023, time.March, 1, 11, 0, 0, 0, time.UTC),
+			TransactionID: "tid_2",
+			Annotations:   []annotations.Annotation{{Predicate: "http://www.ft.com/ontology/hasBrand", ConceptId: patchConceptB}},
+		},
+	}
+	for _, v := range versions {
+		err := store.Append(context.Background(), patchContentUUID, v)
+		assert.NoError(t, err)
+	}
+	return store
+}
+
+func TestListVersions(t *testing.T) {
+	r := newHistoryRouter(new(RWMock), newTestHistory(t))
+
+	req := httptest.NewRequest("GET", "/drafts/content/"+patchContentUUID+"/annotations/versions", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	actual := handler.VersionsResponse{}
+	err := json.NewDecoder(resp.Body).Decode(&actual)
+	assert.NoError(t, err)
+	assert.Equal(t, []history.Version{
+		{Hash: "hash-1", Timestamp: time.Date(2023, time.March, 1, 10, 0, 0, 0, time.UTC), TransactionID: "tid_1"},
+		{Hash: "hash-2", Timestamp: time.Date(2023, time.March, 1, 11, 0, 0, 0, time.UTC), TransactionID: "tid_2"},
+	}, actual.Versions)
+}
+
+func TestReadAnnotationsVersion(t *testing.T) {
+	tests := map[string]struct {
+		query        string
+		expectedHash string
+		expected     []annotations.Annotation
+	}{
+		"by hash": {
+			query:        "version=hash-1",
+			expectedHash: "hash-1",
+			expected:     []annotations.Annotation{{Predicate: patchAbout, ConceptId: patchConceptA}},
+		},
+		"as of": {
+			query:        "asOf=2023-03-01T11:30:00Z",
+			expectedHash: "hash-2",
+			expected:     []annotations.Annotation{{Predicate: "http://www.ft.com/ontology/classification/isClassifiedBy", ConceptId: patchConceptB}},
+		},
+		"as of with hasBrand": {
+			query:        "asOf=2023-03-01T11:30:00Z&sendHasBrand=true",
+			expectedHash: "hash-2",
+			expected:     []annotations.Annotation{{Predicate: "http://www.ft.com/ontology/hasBrand", ConceptId: patchConceptB}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rw := new(RWMock)
+			r := newHistoryRouter(rw, newTestHistory(t))
+
+			req := httptest.NewRequest("GET", "/drafts/content/"+patchContentUUID+"/annotations?"+test.query, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			resp := w.Result()
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, test.expectedHash, resp.Header.Get(handler.VersionHashHeader))
+			assert.Empty(t, resp.Header.Get(annotations.DocumentHashHeader))
+
+			actual := annotations.Annotations{}
+			err := json.NewDecoder(resp.Body).Decode(&actual)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, actual.Annotations)
+
+			rw.AssertNotCalled(t, "Read", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestUnhappyReadAnnotationsVersion(t *testing.T) {
+	tests := map[string]struct {
+		path           string
+		historyEnabled bool
+		expectedStatus int
+	}{
+		"unknown version": {
+			path:           "/annotations?version=hash-3",
+			historyEnabled: true,
+			expectedStatus: http.StatusNotFound,
+		},
+		"before the first version": {
+			path:           "/annotations?asOf=2023-03-01T09:00:00Z",
+			historyEnabled: true,
+			expectedStatus: http.StatusNotFound,
+		},
+		"invalid asOf": {
+			path:           "/annotations?asOf=yesterday",
+			historyEnabled: true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"both version and asOf": {
+			path:           "/annotations?version=hash-1&asOf=2023-03-01T11:30:00Z",
+			historyEnabled: true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"read version without history": {
+			path:           "/annotations?version=hash-1",
+			expectedStatus: http.StatusNotImplemented,
+		},
+		"list versions without history": {
+			path:           "/annotations/versions",
+			expectedStatus: http.StatusNotImplemented,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var store history.Store
+			if test.historyEnabled {
+				store = newTestHistory(t)
+			}
+			r := newHistoryRouter(new(RWMock), store)
+
+			req := httptest.NewRequest("GET", "/drafts/content/"+patchContentUUID+test.path, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, test.expectedStatus, w.Result().StatusCode)
+		})
+	}
+}
+
+func newUndoRouter(rw *RWMock, aug *AugmenterMock, store history.Store) *vestigo.Router {
+	var opts []handler.Option
+	if store != nil {
+		opts = append(opts, handler.WithHistory(store))
+	}
+	h := handler.New(rw, new(AnnotationsAPIMock), annotations.NewCanonicalizer(annotations.NewCanonicalAnnotationSorter), aug, time.Second, opts...)
+	r := vestigo.NewRouter()
+	r.Post("/drafts/content/:uuid/annotations/undo", h.UndoAnnotations)
+	r.Post("/drafts/content/:uuid/annotations/restore", h.RestoreAnnotations)
+	return r
+}
+
+func newUndoHistory(t *testing.T) history.Store {
+	store := history.NewMemoryStore(0)
+	versions := []history.Version{
+		{Hash: "hash-1", Annotations: []annotations.Annotation{{Predicate: patchAbout, ConceptId: patchConceptA}}},
+		{Hash: "hash-2", Parent: "hash-1", Annotations: []annotations.Annotation{
+			{Predicate: patchAbout, ConceptId: patchConceptA},
+			{Predicate: patchMentions, ConceptId: patchConceptB},
+		}},
+		{Hash: "hash-3", Parent: "hash-2", Annotations: []annotations.Annotation{{Predicate: patchMentions, ConceptId: patchConceptB}}},
+	}
+	for _, v := range versions {
+		err := store.Append(context.Background(), patchContentUUID, v)
+		assert.NoError(t, err)
+	}
+	return store
+}
+
+func newUndoRequest(path string, oldHash string) *http.Request {
+	req := httptest.NewRequest("POST", "/drafts/content/"+patchContentUUID+"/annotations/"+path, nil)
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	if oldHash != "" {
+		req.Header.Set(annotations.PreviousDocumentHashHeader, oldHash)
+	}
+	return req
+}
+
+func TestUndoAnnotations(t *testing.T) {
+	store := newUndoHistory(t)
+	version1 := &annotations.Annotations{Annotations: []annotations.Annotation{{Predicate: patchAbout, ConceptId: patchConceptA}}}
+	version2 := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+		{Predicate: patchMentions, ConceptId: patchConceptB},
+	}}
+
+	rw := new(RWMock)
+	rw.On("Write", mock.Anything, patchContentUUID, version2, "hash-3").Return("hash-2", nil).Once()
+	rw.On("Write", mock.Anything, patchContentUUID, version1, "hash-2").Return("hash-1", nil).Once()
+	aug := &AugmenterMock{
+		augment: func(ctx context.Context, depletedAnnotations []annotations.Annotation) ([]annotations.Annotation, error) {
+			return depletedAnnotations, nil
+		},
+	}
+
+	r := newUndoRouter(rw, aug, store)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newUndoRequest("undo", "hash-3"))
+	resp := w.Result()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "hash-2", resp.Header.Get(annotations.DocumentHashHeader))
+
+	actual := annotations.Annotations{}
+	err := json.NewDecoder(resp.Body).Decode(&actual)
+	assert.NoError(t, err)
+	assert.Equal(t, *version2, actual)
+
+	// undoing again goes back to the first version rather than redoing the last one
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, newUndoRequest("undo", "hash-2"))
+	resp = w.Result()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "hash-1", resp.Header.Get(annotations.DocumentHashHeader))
+
+	// there is nothing before the first version
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, newUndoRequest("undo", "hash-1"))
+	assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+
+	versions, err := store.List(context.Background(), patchContentUUID)
+	assert.NoError(t, err)
+	assert.Len(t, versions, 5)
+	assert.Equal(t, "hash-1", versions[3].Parent)
+	assert.Equal(t, "hash-2", versions[3].RestoredFrom)
+	assert.Equal(t, "", versions[4].Parent)
+	assert.Equal(t, "hash-1", versions[4].RestoredFrom)
+
+	rw.AssertExpectations(t)
+}
+
+func TestUndoAnnotationsMetadataChange(t *testing.T) {
+	store := newUndoHistory(t)
+	// the provenance change of the last version does not change its hash
+	err := store.Append(context.Background(), patchContentUUID, history.Version{Hash: "hash-3", Parent: "hash-3", Annotations: []annotations.Annotation{
+		{Predicate: patchMentions, ConceptId: patchConceptB, Provenance: "human"},
+	}})
+	assert.NoError(t, err)
+	version3 := &annotations.Annotations{Annotations: []annotations.Annotation{{Predicate: patchMentions, ConceptId: patchConceptB}}}
+
+	rw := new(RWMock)
+	rw.On("Write", mock.Anything, patchContentUUID, version3, "hash-3").Return("hash-3", nil).Once()
+	aug := &AugmenterMock{
+		augment: func(ctx context.Context, depletedAnnotations []annotations.Annotation) ([]annotations.Annotation, error) {
+			return depletedAnnotations, nil
+		},
+	}
+
+	r := newUndoRouter(rw, aug, store)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newUndoRequest("undo", "hash-3"))
+	resp := w.Result()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	versions, err := store.List(context.Background(), patchContentUUID)
+	assert.NoError(t, err)
+	assert.Len(t, versions, 5)
+	// the undo restores the version before the provenance change, not the change itself
+	assert.Equal(t, "hash-2", versions[4].Parent)
+
+	rw.AssertExpectations(t)
+}
+
+func TestUndoAnnotationsWithoutPreviousHash(t *testing.T) {
+	// the current draft is the second version, although the third one is the most recent in the history
+	current := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+		{Predicate: patchMentions, ConceptId: patchConceptB},
+	}}
+	version1 := &annotations.Annotations{Annotations: []annotations.Annotation{{Predicate: patchAbout, ConceptId: patchConceptA}}}
+
+	rw := new(RWMock)
+	rw.On("Read", mock.Anything, patchContentUUID).Return(current, "hash-2", true, nil).Once()
+	rw.On("Write", mock.Anything, patchContentUUID, version1, "hash-2").Return("hash-1", nil).Once()
+	aug := &AugmenterMock{
+		augment: func(ctx context.Context, depletedAnnotations []annotations.Annotation) ([]annotations.Annotation, error) {
+			return depletedAnnotations, nil
+		},
+	}
+
+	r := newUndoRouter(rw, aug, newUndoHistory(t))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newUndoRequest("undo", ""))
+	resp := w.Result()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "hash-1", resp.Header.Get(annotations.DocumentHashHeader))
+
+	rw.AssertExpectations(t)
+}
+
+func TestUndoAnnotationsDroppingMissingConcepts(t *testing.T) {
+	tests := map[string][]handler.Option{
+		"lenient": nil,
+		"strict":  {handler.WithStrictConcepts()},
+	}
+
+	for name, opts := range tests {
+		t.Run(name, func(t *testing.T) {
+			version2 := &annotations.Annotations{Annotations: []annotations.Annotation{
+				{Predicate: patchAbout, ConceptId: patchConceptA},
+			}}
+
+			rw := new(RWMock)
+			rw.On("Write", mock.Anything, patchContentUUID, version2, "hash-3").Return("hash-4", nil).Once()
+			// concept B no longer exists
+			aug := &AugmenterMock{}
+			aug.augment = func(ctx context.Context, depletedAnnotations []annotations.Annotation) ([]annotations.Annotation, error) {
+				var existing []annotations.Annotation
+				aug.unresolved = nil
+				for _, ann := range depletedAnnotations {
+					if ann.ConceptId == patchConceptB {
+						aug.unresolved = []annotations.UnresolvedConcept{{ConceptId: patchConceptB, Predicates: []string{ann.Predicate}}}
+						continue
+					}
+					existing = append(existing, ann)
+				}
+				return existing, nil
+			}
+
+			opts = append(opts, handler.WithHistory(newUndoHistory(t)))
+			h := handler.New(rw, new(AnnotationsAPIMock), annotations.NewCanonicalizer(annotations.NewCanonicalAnnotationSorter), aug, time.Second, opts...)
+			r := vestigo.NewRouter()
+			r.Post("/drafts/content/:uuid/annotations/undo", h.UndoAnnotations)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newUndoRequest("undo", "hash-3"))
+			resp := w.Result()
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, "hash-4", resp.Header.Get(annotations.DocumentHashHeader))
+
+			actual := handler.AnnotationsResponse{}
+			err := json.NewDecoder(resp.Body).Decode(&actual)
+			assert.NoError(t, err)
+			assert.Equal(t, version2.Annotations, actual.Annotations)
+			assert.Empty(t, actual.Unresolved)
+
+			rw.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRestoreAnnotations(t *testing.T) {
+	store := newUndoHistory(t)
+	version1 := &annotations.Annotations{Annotations: []annotations.Annotation{{Predicate: patchAbout, ConceptId: patchConceptA}}}
+
+	rw := new(RWMock)
+	rw.On("Write", mock.Anything, patchContentUUID, version1, "hash-3").Return("hash-1", nil).Once()
+	aug := &AugmenterMock{
+		augment: func(ctx context.Context, depletedAnnotations []annotations.Annotation) ([]annotations.Annotation, error) {
+			return depletedAnnotations, nil
+		},
+	}
+
+	r := newUndoRouter(rw, aug, store)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newUndoRequest("restore?hash=hash-1", "hash-3"))
+	resp := w.Result()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "hash-1", resp.Header.Get(annotations.DocumentHashHeader))
+
+	versions, err := store.List(context.Background(), patchContentUUID)
+	assert.NoError(t, err)
+	assert.Len(t, versions, 4)
+	assert.Equal(t, "hash-3", versions[3].Parent)
+	assert.Equal(t, "hash-1", versions[3].RestoredFrom)
+
+	rw.AssertExpectations(t)
+}
+
+func TestUnhappyUndoAndRestoreAnnotations(t *testing.T) {
+	tests := map[string]struct {
+		path           string
+		oldHash        string
+		historyEnabled bool
+		writeErr       error
+		expectedStatus int
+	}{
+		"history not enabled": {
+			path:           "undo",
+			expectedStatus: http.StatusNotImplemented,
+		},
+		"unknown current version": {
+			path:           "undo",
+			oldHash:        "hash-5",
+			historyEnabled: true,
+			expectedStatus: http.StatusNotFound,
+		},
+		"missing restore hash": {
+			path:           "restore",
+			historyEnabled: true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"unknown restore hash": {
+			path:           "restore?hash=hash-5",
+			historyEnabled: true,
+			expectedStatus: http.StatusNotFound,
+		},
+		"write error": {
+			path:           "restore?hash=hash-1",
+			historyEnabled: true,
+			writeErr:       errors.New("computer says no"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var store history.Store
+			if test.historyEnabled {
+				store = newUndoHistory(t)
+			}
+			rw := new(RWMock)
+			rw.On("Read", mock.Anything, patchContentUUID).Return(nil, "", false, nil)
+			rw.On("Write", mock.Anything, patchContentUUID, mock.Anything, test.oldHash).Return("", test.writeErr)
+			aug := &AugmenterMock{
+				augment: func(ctx context.Context, depletedAnnotations []annotations.Annotation) ([]annotations.Annotation, error) {
+					return depletedAnnotations, nil
+				},
+			}
+
+			r := newUndoRouter(rw, aug, store)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newUndoRequest(test.path, test.oldHash))
+			assert.Equal(t, test.expectedStatus, w.Result().StatusCode)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Financial-Times/draft-annotations-api/annotations"
+	"github.com/Financial-Times/draft-annotations-api/history"
+	log "github.com/sirupsen/logrus"
+)
+
+// ConflictResponse is the body of the response returned when concurrent changes to the draft annotations
+// cannot be merged or the Previous-Document-Hash is stale.
+// CurrentHash is the hash of the current draft, to be used as Previous-Document-Hash on retry.
+type ConflictResponse struct {
+	Message     string                      `json:"message"`
+	CurrentHash string                      `json:"currentHash"`
+	Conflicts   []annotations.MergeConflict `json:"conflicts"`
+}
+
+// conflictError is returned when the draft annotations have been changed concurrently
+// and the changes cannot be merged, or the Previous-Document-Hash is stale.
+type conflictError struct {
+	status      int
+	reason      string
+	currentHash string
+	conflicts   []annotations.MergeConflict
+}
+
+func (e *conflictError) Error() string {
+	return e.reason
+}
+
+// mergeAndWrite is called when the write of the given annotations has been rejected because the draft has been changed
+// since oldHash, with an HTTP 409 or 412 from the annotations RW. It three-way merges the given annotations and the
+// current draft, using the version with oldHash from the history as base, and writes the result. It returns the written
+// annotations, their hash and the draft they have been merged with, which the write has replaced.
+// If the base version is not available, it returns a conflictError with the given status of the rejection.
+func (h *Handler) mergeAndWrite(ctx context.Context, contentUUID string, ours []annotations.Annotation, oldHash string, status int, writeLog *log.Entry) (*annotations.Annotations, string, previousDraft, error) {
+	writeLog.Debug("Reading current draft from annotations RW...")
+	current, currentHash, hasDraft, err := h.annotationsRW.Read(ctx, contentUUID)
+	if err != nil {
+		return nil, "", previousDraft{}, err
+	}
+	var theirs []annotations.Annotation
+	if hasDraft {
+		theirs = current.Annotations
+	}
+
+	base, err := h.baseVersion(ctx, contentUUID, oldHash)
+	if err != nil {
+		return nil, "", previousDraft{}, &conflictError{
+			status:      status,
+			reason:      fmt.Sprintf("draft annotations have been changed concurrently and cannot be merged: %v", err),
+			currentHash: currentHash,
+			conflicts:   []annotations.MergeConflict{},
+		}
+	}
+
+	merged, conflicts := annotations.Merge(base.Annotations, ours, theirs)
+	if len(conflicts) > 0 {
+		return nil, "", previousDraft{}, &conflictError{
+			status:      http.StatusConflict,
+			reason:      "draft annotations have been changed concurrently with conflicting changes",
+			currentHash: currentHash,
+			conflicts:   conflicts,
+		}
+	}
+
+	writeLog.Debug("Writing merged annotations to annotations RW...")
+	newAnnotations := &annotations.Annotations{Annotations: h.c14n.Canonicalize(merged)}
+	newHash, err := h.annotationsRW.Write(ctx, contentUUID, newAnnotations, currentHash)
+	if err != nil {
+		return nil, "", previousDraft{}, err
+	}
+	return newAnnotations, newHash, previousDraft{annotations: h.c14n.Canonicalize(theirs), hash: currentHash}, nil
+}
+
+// baseVersion returns the version with the given hash from the history.
+func (h *Handler) baseVersion(ctx context.Context, contentUUID string, hash string) (history.Version, error) {
+	if h.history == nil {
+		return history.Version{}, errors.New("draft annotations history is not enabled")
+	}
+	if hash == "" {
+		return history.Version{}, errors.New("previous document hash not provided")
+	}
+	versions, err := h.history.List(ctx, contentUUID)
+	if err != nil {
+		return history.Version{}, err
+	}
+	return history.FindByHash(versions, hash)
+}
+
+// staleHashError turns the errors returned by the annotations RW for a stale Previous-Document-Hash into a conflictError
+// holding the hash of the current draft, so that clients can recover. Other errors are returned unchanged.
+func (h *Handler) staleHashError(ctx context.Context, contentUUID string, err error) error {
+	var status int
+	switch {
+	case errors.Is(err, annotations.ErrConflict):
+		status = http.StatusConflict
+	case errors.Is(err, annotations.ErrPreconditionFailed):
+		status = http.StatusPreconditionFailed
+	default:
+		return err
+	}
+
+	var currentHash string
+	var rwErr annotations.RWError
+	if errors.As(err, &rwErr) {
+		currentHash = rwErr.CurrentHash()
+	}
+	if currentHash == "" {
+		_, hash, _, readErr := h.annotationsRW.Read(ctx, contentUUID)
+		if readErr != nil {
+			log.WithError(readErr).WithField("uuid", contentUUID).Warn("Failed to read the current draft hash after a stale hash error")
+		}
+		currentHash = hash
+	}
+
+	return &conflictError{
+		status:      status,
+		reason:      err.Error(),
+		currentHash: currentHash,
+		conflicts:   []annotations.MergeConflict{},
+	}
+}
+
+func writeConflict(w http.ResponseWriter, msg string, conflictErr *conflictError) {
+	if conflictErr.currentHash != "" {
+		w.Header().Set(annotations.DocumentHashHeader, conflictErr.currentHash)
+	}
+	w.WriteHeader(conflictErr.status)
+
+	err := json.NewEncoder(w).Encode(&ConflictResponse{
+		Message:     msg,
+		CurrentHash: conflictErr.currentHash,
+		Conflicts:   conflictErr.conflicts,
+	})
+	if err != nil {
+		log.WithError(err).Error("Failed to encode conflict response.")
+	}
+}
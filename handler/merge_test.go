@@ -0,0 +1,269 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Financial-Times/draft-annotations-api/annotations"
+	"github.com/Financial-Times/draft-annotations-api/handler"
+	"github.com/Financial-Times/draft-annotations-api/history"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/husobee/vestigo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var errTestConflict = annotations.NewRWError(http.StatusConflict, "", annotations.ErrConflict)
+
+func newMergeRouter(rw *RWMock, store history.Store) *vestigo.Router {
+	aug := &AugmenterMock{
+		augment: func(ctx context.Context, depletedAnnotations []annotations.Annotation) ([]annotations.Annotation, error) {
+			return depletedAnnotations, nil
+		},
+	}
+	var opts []handler.Option
+	if store != nil {
+		opts = append(opts, handler.WithHistory(store))
+	}
+	h := handler.New(rw, new(AnnotationsAPIMock), annotations.NewCanonicalizer(annotations.NewCanonicalAnnotationSorter), aug, time.Second, opts...)
+	r := vestigo.NewRouter()
+	r.Put("/drafts/content/:uuid/annotations", h.WriteAnnotations)
+	return r
+}
+
+func newMergeHistory(t *testing.T) history.Store {
+	store := history.NewMemoryStore(0)
+	err := store.Append(context.Background(), patchContentUUID, history.Version{
+		Hash: "base-hash",
+		Annotations: []annotations.Annotation{
+			{Predicate: patchAbout, ConceptId: patchConceptA},
+			{Predicate: patchMentions, ConceptId: patchConceptB},
+		},
+	})
+	assert.NoError(t, err)
+	return store
+}
+
+func newMergeRequest(body string) *http.Request {
+	req := httptest.NewRequest("PUT", "/drafts/content/"+patchContentUUID+"/annotations", strings.NewReader(body))
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	req.Header.Set(annotations.PreviousDocumentHashHeader, "base-hash")
+	return req
+}
+
+func TestWriteAnnotationsMergesConcurrentChanges(t *testing.T) {
+	store := newMergeHistory(t)
+	ours := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+		{Predicate: patchAbout, ConceptId: patchConceptC},
+		{Predicate: patchMentions, ConceptId: patchConceptB},
+	}}
+	// the other editor has removed concept B
+	theirs := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+	}}
+	merged := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+		{Predicate: patchAbout, ConceptId: patchConceptC},
+	}}
+
+	rw := new(RWMock)
+	rw.On("Write", mock.Anything, patchContentUUID, ours, "base-hash").Return("", errTestConflict).Once()
+	rw.On("Read", mock.Anything, patchContentUUID).Return(theirs, "their-hash", true, nil).Once()
+	rw.On("Write", mock.Anything, patchContentUUID, merged, "their-hash").Return("merged-hash", nil).Once()
+
+	r := newMergeRouter(rw, store)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newMergeRequest(`{"annotations":[
+		{"predicate":"`+patchAbout+`","id":"`+patchConceptA+`"},
+		{"predicate":"`+patchMentions+`","id":"`+patchConceptB+`"},
+		{"predicate":"`+patchAbout+`","id":"`+patchConceptC+`"}
+	]}`))
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "merged-hash", resp.Header.Get(annotations.DocumentHashHeader))
+
+	actual := annotations.Annotations{}
+	err := json.NewDecoder(resp.Body).Decode(&actual)
+	assert.NoError(t, err)
+	assert.Equal(t, *merged, actual)
+
+	versions, err := store.List(context.Background(), patchContentUUID)
+	assert.NoError(t, err)
+	assert.Len(t, versions, 2)
+	assert.Equal(t, "merged-hash", versions[1].Hash)
+	assert.Equal(t, "their-hash", versions[1].Parent)
+	assert.Equal(t, merged.Annotations, versions[1].Annotations)
+
+	rw.AssertExpectations(t)
+}
+
+func TestWriteAnnotationsConflictingChanges(t *testing.T) {
+	ours := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+		{Predicate: patchAbout, ConceptId: patchConceptB},
+	}}
+	theirs := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+	}}
+
+	rw := new(RWMock)
+	rw.On("Write", mock.Anything, patchContentUUID, ours, "base-hash").Return("", errTestConflict).Once()
+	rw.On("Read", mock.Anything, patchContentUUID).Return(theirs, "their-hash", true, nil).Once()
+
+	r := newMergeRouter(rw, newMergeHistory(t))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newMergeRequest(`{"annotations":[
+		{"predicate":"`+patchAbout+`","id":"`+patchConceptA+`"},
+		{"predicate":"`+patchAbout+`","id":"`+patchConceptB+`"}
+	]}`))
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, "their-hash", resp.Header.Get(annotations.DocumentHashHeader))
+
+	actual := handler.ConflictResponse{}
+	err := json.NewDecoder(resp.Body).Decode(&actual)
+	assert.NoError(t, err)
+	assert.Equal(t, "their-hash", actual.CurrentHash)
+	assert.Equal(t, []annotations.MergeConflict{
+		{
+			ConceptId: patchConceptB,
+			Base:      []string{patchMentions},
+			Ours:      []string{patchAbout},
+			Theirs:    []string{},
+		},
+	}, actual.Conflicts)
+
+	rw.AssertExpectations(t)
+}
+
+func TestWriteAnnotationsConflictWithoutBaseVersion(t *testing.T) {
+	ours := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+	}}
+
+	rw := new(RWMock)
+	rw.On("Write", mock.Anything, patchContentUUID, ours, "base-hash").Return("", errTestConflict).Once()
+	rw.On("Read", mock.Anything, patchContentUUID).Return(ours, "their-hash", true, nil).Once()
+
+	r := newMergeRouter(rw, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newMergeRequest(`{"annotations":[{"predicate":"`+patchAbout+`","id":"`+patchConceptA+`"}]}`))
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	actual := handler.ConflictResponse{}
+	err := json.NewDecoder(resp.Body).Decode(&actual)
+	assert.NoError(t, err)
+	assert.Equal(t, "their-hash", actual.CurrentHash)
+	assert.Empty(t, actual.Conflicts)
+
+	rw.AssertExpectations(t)
+}
+
+func TestWriteAnnotationsConflictWhileMerging(t *testing.T) {
+	ours := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+		{Predicate: patchMentions, ConceptId: patchConceptB},
+	}}
+	theirs := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+	}}
+
+	rw := new(RWMock)
+	rw.On("Write", mock.Anything, patchContentUUID, ours, "base-hash").Return("", errTestConflict).Once()
+	rw.On("Read", mock.Anything, patchContentUUID).Return(theirs, "their-hash", true, nil).Once()
+	rw.On("Write", mock.Anything, patchContentUUID, theirs, "their-hash").
+		Return("", annotations.NewRWError(http.StatusConflict, "newer-hash", annotations.ErrConflict)).Once()
+
+	r := newMergeRouter(rw, newMergeHistory(t))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newMergeRequest(`{"annotations":[
+		{"predicate":"`+patchAbout+`","id":"`+patchConceptA+`"},
+		{"predicate":"`+patchMentions+`","id":"`+patchConceptB+`"}
+	]}`))
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, "newer-hash", resp.Header.Get(annotations.DocumentHashHeader))
+
+	rw.AssertExpectations(t)
+}
+
+func TestWriteAnnotationsMergesOnPreconditionFailed(t *testing.T) {
+	ours := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+		{Predicate: patchAbout, ConceptId: patchConceptC},
+		{Predicate: patchMentions, ConceptId: patchConceptB},
+	}}
+	theirs := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+	}}
+	merged := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+		{Predicate: patchAbout, ConceptId: patchConceptC},
+	}}
+	errPreconditionFailed := annotations.NewRWError(http.StatusPreconditionFailed, "", annotations.ErrPreconditionFailed)
+
+	tests := map[string]struct {
+		store          history.Store
+		expectedStatus int
+	}{
+		"merged": {
+			store:          newMergeHistory(t),
+			expectedStatus: http.StatusOK,
+		},
+		"without history": {
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rw := new(RWMock)
+			rw.On("Write", mock.Anything, patchContentUUID, ours, "base-hash").Return("", errPreconditionFailed).Once()
+			rw.On("Read", mock.Anything, patchContentUUID).Return(theirs, "their-hash", true, nil).Once()
+			if test.store != nil {
+				rw.On("Write", mock.Anything, patchContentUUID, merged, "their-hash").Return("merged-hash", nil).Once()
+			}
+
+			r := newMergeRouter(rw, test.store)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newMergeRequest(`{"annotations":[
+				{"predicate":"`+patchAbout+`","id":"`+patchConceptA+`"},
+				{"predicate":"`+patchMentions+`","id":"`+patchConceptB+`"},
+				{"predicate":"`+patchAbout+`","id":"`+patchConceptC+`"}
+			]}`))
+			resp := w.Result()
+			defer resp.Body.Close()
+			assert.Equal(t, test.expectedStatus, resp.StatusCode)
+			if test.store == nil {
+				assert.Equal(t, "their-hash", resp.Header.Get(annotations.DocumentHashHeader))
+
+				actual := handler.ConflictResponse{}
+				err := json.NewDecoder(resp.Body).Decode(&actual)
+				assert.NoError(t, err)
+				assert.Equal(t, "their-hash", actual.CurrentHash)
+				assert.Contains(t, actual.Message, "history is not enabled")
+				assert.Empty(t, actual.Conflicts)
+			} else {
+				assert.Equal(t, "merged-hash", resp.Header.Get(annotations.DocumentHashHeader))
+			}
+
+			rw.AssertExpectations(t)
+		})
+	}
+}
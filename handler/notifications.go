@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Financial-Times/draft-annotations-api/events"
+	"github.com/Financial-Times/draft-annotations-api/notifications"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	log "github.com/sirupsen/logrus"
+)
+
+// notificationsPageSize is the maximum number of notifications returned by each request to the notifications feed.
+const notificationsPageSize = 50
+
+// NotificationsResponse is the body of the notifications feed response.
+// The next page of the feed is given by the link with the "next" relation.
+type NotificationsResponse struct {
+	RequestURL    string                       `json:"requestUrl"`
+	Notifications []notifications.Notification `json:"notifications"`
+	Links         []Link                       `json:"links"`
+}
+
+// Link is a link to a related page of a feed.
+type Link struct {
+	Href string `json:"href"`
+	Rel  string `json:"rel"`
+}
+
+// ReadNotifications returns a page of the notifications of the changes to the draft annotations of all contents
+// made after the time given with the since query parameter, or following the feed from the cursor query parameter
+// of the next link of a previous page, in the order they have been recorded.
+func (h *Handler) ReadNotifications(w http.ResponseWriter, r *http.Request) {
+	tID := tidutils.GetTransactionIDFromRequest(r)
+
+	ctx, cancel := context.WithTimeout(tidutils.TransactionAwareContext(r.Context(), tID), h.timeout)
+	defer cancel()
+
+	readLog := log.WithField(tidutils.TransactionIDKey, tID)
+
+	w.Header().Add("Content-Type", "application/json")
+
+	if h.notifications == nil {
+		writeMessage(w, "Draft annotations notifications are not enabled", http.StatusNotImplemented)
+		return
+	}
+
+	sinceParam := r.URL.Query().Get("since")
+	cursorParam := r.URL.Query().Get("cursor")
+	if (sinceParam == "") == (cursorParam == "") {
+		writeMessage(w, "either the since or the cursor param is required", http.StatusBadRequest)
+		return
+	}
+
+	var page []notifications.Notification
+	var next notifications.Cursor
+	var err error
+	query := url.Values{}
+	if sinceParam != "" {
+		since, parseErr := time.Parse(time.RFC3339Nano, sinceParam)
+		if parseErr != nil {
+			writeMessage(w, fmt.Sprintf("invalid param since: %s ", sinceParam), http.StatusBadRequest)
+			return
+		}
+		query.Set("since", since.UTC().Format(time.RFC3339Nano))
+		page, next, err = h.notifications.Since(ctx, since, notificationsPageSize)
+	} else {
+		cursor, parseErr := strconv.ParseUint(cursorParam, 10, 64)
+		if parseErr != nil {
+			writeMessage(w, fmt.Sprintf("invalid param cursor: %s ", cursorParam), http.StatusBadRequest)
+			return
+		}
+		query.Set("cursor", cursorParam)
+		page, next, err = h.notifications.After(ctx, notifications.Cursor(cursor), notificationsPageSize)
+	}
+	if err != nil {
+		readLog.WithError(err).Error("Failed to read draft annotations notifications")
+		writeMessage(w, fmt.Sprintf("Failed to read draft annotations notifications: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	nextQuery := url.Values{}
+	nextQuery.Set("cursor", strconv.FormatUint(uint64(next), 10))
+	response := NotificationsResponse{
+		RequestURL:    notificationsURL(r, query),
+		Notifications: page,
+		Links:         []Link{{Href: notificationsURL(r, nextQuery), Rel: "next"}},
+	}
+
+	err = json.NewEncoder(w).Encode(&response)
+	if err != nil {
+		readLog.WithError(err).Error("Failed to encode response")
+		handleReadErrors(err, readLog, w)
+	}
+}
+
+// notificationsURL returns the URL of the page of the notifications feed selected by the given query.
+func notificationsURL(r *http.Request, query url.Values) string {
+	return r.URL.Path + "?" + query.Encode()
+}
+
+// recordNotification records the given change in the notifications feed, if enabled.
+// A failing store only leaves the change out of the feed, so its error is logged and the write still succeeds.
+func (h *Handler) recordNotification(ctx context.Context, event events.Event, writeLog *log.Entry) {
+	if h.notifications == nil {
+		return
+	}
+
+	err := h.notifications.Append(ctx, notifications.Notification{
+		Type:             event.Type,
+		ContentUUID:      event.ContentUUID,
+		Hash:             event.Hash,
+		PublishReference: event.TransactionID,
+		LastModified:     event.Timestamp,
+	})
+	if err != nil {
+		writeLog.WithError(err).Warn("Failed to record draft annotations notification")
+	}
+}
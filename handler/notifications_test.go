@@ -0,0 +1,154 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Financial-Times/draft-annotations-api/annotations"
+	"github.com/Financial-Times/draft-annotations-api/events"
+	"github.com/Financial-Times/draft-annotations-api/handler"
+	"github.com/Financial-Times/draft-annotations-api/notifications"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/husobee/vestigo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newNotificationsRouter(rw *RWMock, store notifications.Store) *vestigo.Router {
+	aug := &AugmenterMock{
+		augment: func(ctx context.Context, depletedAnnotations []annotations.Annotation) ([]annotations.Annotation, error) {
+			return depletedAnnotations, nil
+		},
+	}
+	var opts []handler.Option
+	if store != nil {
+		opts = append(opts, handler.WithNotifications(store))
+	}
+	h := handler.New(rw, new(AnnotationsAPIMock), annotations.NewCanonicalizer(annotations.NewCanonicalAnnotationSorter), aug, time.Second, opts...)
+	r := vestigo.NewRouter()
+	r.Put("/drafts/content/:uuid/annotations", h.WriteAnnotations)
+	r.Delete("/drafts/content/:uuid/annotations", h.DiscardDraftAnnotations)
+	r.Get("/drafts/notifications", h.ReadNotifications)
+	return r
+}
+
+func readNotifications(t *testing.T, r *vestigo.Router, target string) handler.NotificationsResponse {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", target, nil))
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	actual := handler.NotificationsResponse{}
+	err := json.NewDecoder(resp.Body).Decode(&actual)
+	assert.NoError(t, err)
+	return actual
+}
+
+func TestWriteAnnotationsRecordsNotifications(t *testing.T) {
+	draft := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+	}}
+
+	rw := new(RWMock)
+	rw.On("Write", mock.Anything, patchContentUUID, draft, "old-hash").Return("new-hash", nil)
+	rw.On("Delete", mock.Anything, patchContentUUID, "new-hash").Return(nil)
+
+	r := newNotificationsRouter(rw, notifications.NewMemoryStore(0))
+	start := time.Now().UTC()
+
+	req := httptest.NewRequest("PUT", "/drafts/content/"+patchContentUUID+"/annotations", strings.NewReader(`{"annotations":[{"predicate":"`+patchAbout+`","id":"`+patchConceptA+`"}]}`))
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	req.Header.Set(annotations.PreviousDocumentHashHeader, "old-hash")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+	req = httptest.NewRequest("DELETE", "/drafts/content/"+patchContentUUID+"/annotations", nil)
+	req.Header.Set(annotations.PreviousDocumentHashHeader, "new-hash")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Result().StatusCode)
+
+	since := start.Add(-time.Second).Format(time.RFC3339)
+	actual := readNotifications(t, r, "/drafts/notifications?since="+since)
+	assert.Equal(t, "/drafts/notifications?since="+url.QueryEscape(since), actual.RequestURL)
+	assert.Len(t, actual.Notifications, 2)
+	assert.Equal(t, events.Saved, actual.Notifications[0].Type)
+	assert.Equal(t, patchContentUUID, actual.Notifications[0].ContentUUID)
+	assert.Equal(t, "new-hash", actual.Notifications[0].Hash)
+	assert.Equal(t, testTID, actual.Notifications[0].PublishReference)
+	assert.Equal(t, events.Discarded, actual.Notifications[1].Type)
+	assert.Empty(t, actual.Notifications[1].Hash)
+
+	// the next page starts after the last notification
+	assert.Len(t, actual.Links, 1)
+	assert.Equal(t, "next", actual.Links[0].Rel)
+	assert.Equal(t, "/drafts/notifications?cursor=2", actual.Links[0].Href)
+	next := readNotifications(t, r, actual.Links[0].Href)
+	assert.Equal(t, actual.Links[0].Href, next.RequestURL)
+	assert.Empty(t, next.Notifications)
+	assert.Equal(t, actual.Links[0].Href, next.Links[0].Href)
+
+	// a change made after reading the page is on the next one
+	req = httptest.NewRequest("PUT", "/drafts/content/"+patchContentUUID+"/annotations", strings.NewReader(`{"annotations":[{"predicate":"`+patchAbout+`","id":"`+patchConceptA+`"}]}`))
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	req.Header.Set(annotations.PreviousDocumentHashHeader, "old-hash")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+	next = readNotifications(t, r, actual.Links[0].Href)
+	assert.Len(t, next.Notifications, 1)
+	assert.Equal(t, "/drafts/notifications?cursor=3", next.Links[0].Href)
+
+	rw.AssertExpectations(t)
+}
+
+func TestReadNotificationsErrors(t *testing.T) {
+	tests := map[string]struct {
+		store          notifications.Store
+		since          string
+		expectedStatus int
+	}{
+		"missing since": {
+			store:          notifications.NewMemoryStore(0),
+			expectedStatus: http.StatusBadRequest,
+		},
+		"since and cursor": {
+			store:          notifications.NewMemoryStore(0),
+			since:          "2023-03-01T10:00:00Z&cursor=2",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"invalid cursor": {
+			store:          notifications.NewMemoryStore(0),
+			since:          "&cursor=last",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"invalid since": {
+			store:          notifications.NewMemoryStore(0),
+			since:          "yesterday",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"notifications disabled": {
+			since:          "2023-03-01T10:00:00Z",
+			expectedStatus: http.StatusNotImplemented,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := newNotificationsRouter(new(RWMock), test.store)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest("GET", "/drafts/notifications?since="+test.since, nil))
+			assert.Equal(t, test.expectedStatus, w.Result().StatusCode)
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+
+	"github.com/Financial-Times/draft-annotations-api/annotations"
+	"github.com/Financial-Times/draft-annotations-api/mapper"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/husobee/vestigo"
+	log "github.com/sirupsen/logrus"
+)
+
+// PatchAnnotations applies a JSON Patch (RFC 6902) document to the draft annotations of a content.
+// The patch is applied to the annotations returned by the read endpoint, i.e. the current draft, or the published
+// annotations if there is no draft, so that the indexes of the operations refer to the list the client has read.
+// The Previous-Document-Hash is checked against the current draft before the patch is applied.
+// The annotations with unresolved concepts, which are not returned by the read endpoint, are kept as they are.
+// All the operations are applied atomically and the result is written with a single write.
+func (h *Handler) PatchAnnotations(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	contentUUID := vestigo.Param(r, "uuid")
+
+	tID := tidutils.GetTransactionIDFromRequest(r)
+	ctx := auditContext(tidutils.TransactionAwareContext(context.Background(), tID), r)
+	writeLog := log.WithField(tidutils.TransactionIDKey, tID).WithField("uuid", contentUUID)
+
+	oldHash := r.Header.Get(annotations.PreviousDocumentHashHeader)
+
+	if err := validateUUID(contentUUID); err != nil {
+		handleWriteErrors("Invalid content UUID", err, writeLog, w, http.StatusBadRequest)
+		return
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != annotations.JSONPatchContentType {
+		handleWriteErrors("Unsupported content type", fmt.Errorf("expected %s", annotations.JSONPatchContentType), writeLog, w, http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var ops []annotations.PatchOperation
+	err = json.NewDecoder(r.Body).Decode(&ops)
+	if err != nil {
+		handleWriteErrors("Unable to unmarshal JSON patch body", err, writeLog, w, http.StatusBadRequest)
+		return
+	}
+
+	writeLog.Debug("Reading current annotations...")
+	current, unresolved, err := h.patchTarget(ctx, contentUUID, oldHash, writeLog)
+	if err != nil {
+		status, _ := readErrorStatus(err)
+		handleWriteErrors("Error while reading current annotations", err, writeLog, w, status)
+		return
+	}
+
+	patched, err := annotations.ApplyPatch(current, ops)
+	if err != nil {
+		handleWriteErrors("Error applying JSON patch", err, writeLog, w, http.StatusUnprocessableEntity)
+		return
+	}
+
+	for _, ann := range patched {
+		if err := validateAnnotation(ann); err != nil {
+			handleWriteErrors("Invalid annotation in patched list", err, writeLog, w, http.StatusUnprocessableEntity)
+			return
+		}
+	}
+
+	savedAnnotations, newHash, err := h.saveAndReturnAnnotations(ctx, append(patched, unresolved...), writeLog, oldHash, contentUUID)
+	if err != nil {
+		handleWriteErrors("Error writing draft annotations", err, writeLog, w, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set(annotations.DocumentHashHeader, newHash)
+
+	err = json.NewEncoder(w).Encode(savedAnnotations)
+	if err != nil {
+		handleWriteErrors("Error in encoding draft annotations response", err, writeLog, w, http.StatusInternalServerError)
+		return
+	}
+}
+
+// patchTarget returns the annotations the operations of a patch refer to, as the read endpoint returns them,
+// and the annotations with unresolved concepts it leaves out. The draft is read from the annotations RW,
+// not the read RW, and a conflictError is returned if its hash is not the given previous one.
+// Without a draft, the given hash is left to the annotations RW to check, as for the other writes.
+func (h *Handler) patchTarget(ctx context.Context, contentUUID string, oldHash string, writeLog *log.Entry) ([]annotations.Annotation, []annotations.Annotation, error) {
+	current, hash, err := h.fetchAnnotations(ctx, h.annotationsRW, contentUUID, writeLog)
+	if err != nil {
+		return nil, nil, err
+	}
+	if oldHash != "" && hash != "" && hash != oldHash {
+		return nil, nil, &conflictError{
+			status:      http.StatusConflict,
+			reason:      "the draft annotations have changed since the Previous-Document-Hash",
+			currentHash: hash,
+		}
+	}
+
+	result, aug, err := h.augmentForRead(ctx, current, false, writeLog)
+	if err != nil {
+		return nil, nil, err
+	}
+	return canonicalOrder(result), annotations.UnresolvedAnnotations(current, aug.unresolved), nil
+}
+
+func validateAnnotation(ann annotations.Annotation) error {
+	if !mapper.IsValidPACPredicate(ann.Predicate) {
+		return fmt.Errorf("invalid predicate %s", ann.Predicate)
+	}
+	return validateConceptID(ann.ConceptId)
+}
@@ -0,0 +1,345 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Financial-Times/draft-annotations-api/annotations"
+	"github.com/Financial-Times/draft-annotations-api/handler"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/husobee/vestigo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	patchContentUUID = "83a201c6-60cd-11e7-91a7-502f7ee26895"
+	patchConceptA    = "http://www.ft.com/thing/0a619d71-9af5-3755-90dd-f789b686c67a"
+	patchConceptB    = "http://www.ft.com/thing/838b3fbe-efbc-3cfe-b5c0-d38c046492a4"
+	patchConceptC    = "http://www.ft.com/thing/9577c6d4-b09e-4552-b88f-e52745abe02b"
+	patchAbout       = "http://www.ft.com/ontology/annotation/about"
+	patchMentions    = "http://www.ft.com/ontology/annotation/mentions"
+)
+
+func newPatchRouter(rw *RWMock, annAPI *AnnotationsAPIMock) *vestigo.Router {
+	aug := &AugmenterMock{
+		augment: func(ctx context.Context, depletedAnnotations []annotations.Annotation) ([]annotations.Annotation, error) {
+			return depletedAnnotations, nil
+		},
+	}
+	h := handler.New(rw, annAPI, annotations.NewCanonicalizer(annotations.NewCanonicalAnnotationSorter), aug, time.Second)
+	r := vestigo.NewRouter()
+	r.Patch("/drafts/content/:uuid/annotations", h.PatchAnnotations)
+	return r
+}
+
+func newPatchRequest(body string) *http.Request {
+	req := httptest.NewRequest("PATCH", "/drafts/content/"+patchContentUUID+"/annotations", strings.NewReader(body))
+	req.Header.Set("Content-Type", annotations.JSONPatchContentType)
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	req.Header.Set(annotations.PreviousDocumentHashHeader, "old-hash")
+	return req
+}
+
+func TestPatchAnnotations(t *testing.T) {
+	draft := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchMentions, ConceptId: patchConceptB},
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+	}}
+	expected := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptB},
+		{Predicate: patchMentions, ConceptId: patchConceptC},
+	}}
+
+	rw := new(RWMock)
+	rw.On("Read", mock.Anything, patchContentUUID).Return(draft, "old-hash", true, nil)
+	rw.On("Write", mock.Anything, patchContentUUID, expected, "old-hash").Return("new-hash", nil).Once()
+	annAPI := new(AnnotationsAPIMock)
+
+	r := newPatchRouter(rw, annAPI)
+
+	// indexes refer to the canonical order, i.e. about annotations first
+	req := newPatchRequest(`[
+		{"op":"remove","path":"/annotations/0"},
+		{"op":"replace","path":"/annotations/0/predicate","value":"` + patchAbout + `"},
+		{"op":"add","path":"/annotations/-","value":{"predicate":"` + patchMentions + `","id":"` + patchConceptC + `"}}
+	]`)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "new-hash", resp.Header.Get(annotations.DocumentHashHeader))
+
+	actual := annotations.Annotations{}
+	err := json.NewDecoder(resp.Body).Decode(&actual)
+	assert.NoError(t, err)
+	assert.Equal(t, *expected, actual)
+
+	rw.AssertExpectations(t)
+	annAPI.AssertExpectations(t)
+}
+
+func TestPatchAnnotationsWithoutDraft(t *testing.T) {
+	published := []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+	}
+	expected := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+		{Predicate: patchMentions, ConceptId: patchConceptB},
+	}}
+
+	rw := new(RWMock)
+	rw.On("Read", mock.Anything, patchContentUUID).Return(nil, "", false, nil)
+	rw.On("Write", mock.Anything, patchContentUUID, expected, "old-hash").Return("new-hash", nil).Once()
+	annAPI := new(AnnotationsAPIMock)
+	annAPI.On("GetAll", mock.Anything, patchContentUUID).Return(published, nil)
+
+	r := newPatchRouter(rw, annAPI)
+
+	req := newPatchRequest(`[{"op":"add","path":"/annotations/-","value":{"predicate":"` + patchMentions + `","id":"` + patchConceptB + `"}}]`)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "new-hash", resp.Header.Get(annotations.DocumentHashHeader))
+
+	rw.AssertExpectations(t)
+	annAPI.AssertExpectations(t)
+}
+
+func TestPatchAnnotationsStaleHash(t *testing.T) {
+	draft := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+	}}
+
+	rw := new(RWMock)
+	rw.On("Read", mock.Anything, patchContentUUID).Return(draft, "current-hash", true, nil)
+
+	r := newPatchRouter(rw, new(AnnotationsAPIMock))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newPatchRequest(`[{"op":"remove","path":"/annotations/0"}]`))
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, "current-hash", resp.Header.Get(annotations.DocumentHashHeader))
+
+	actual := handler.ConflictResponse{}
+	err := json.NewDecoder(resp.Body).Decode(&actual)
+	assert.NoError(t, err)
+	assert.Equal(t, "current-hash", actual.CurrentHash)
+
+	rw.AssertNotCalled(t, "Write", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPatchAnnotationsAppliesToReadList(t *testing.T) {
+	// the draft holds a duplicate, an invalid predicate, a concept ID which is not canonical and an unresolved concept
+	draft := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchMentions, ConceptId: patchConceptB},
+		{Predicate: patchMentions, ConceptId: patchConceptB},
+		{Predicate: "http://www.ft.com/ontology/annotation/majorMentions", ConceptId: patchConceptA},
+		{Predicate: patchAbout, ConceptId: "http://www.ft.com/thing/e3a4ed4e-67b9-4aa0-8c2b-cd1f5fee9e23"},
+		{Predicate: patchAbout, ConceptId: patchConceptC},
+	}}
+	aug := &AugmenterMock{
+		// dedupes, filters the invalid predicate, canonicalizes the concept ID to A and leaves C unresolved
+		augment: func(_ context.Context, depleted []annotations.Annotation) ([]annotations.Annotation, error) {
+			var augmented []annotations.Annotation
+			seen := make(map[annotations.Annotation]bool)
+			for _, ann := range depleted {
+				if ann.ConceptId == patchConceptC || ann.Predicate != patchAbout && ann.Predicate != patchMentions {
+					continue
+				}
+				if ann.ConceptId == "http://www.ft.com/thing/e3a4ed4e-67b9-4aa0-8c2b-cd1f5fee9e23" {
+					ann.ConceptId = patchConceptA
+				}
+				if !seen[ann] {
+					seen[ann] = true
+					augmented = append(augmented, ann)
+				}
+			}
+			return augmented, nil
+		},
+		unresolved: []annotations.UnresolvedConcept{{ConceptId: patchConceptC, Predicates: []string{patchAbout}}},
+	}
+	var written []annotations.Annotation
+	rw := &RWMock{
+		read: func(_ context.Context, _ string) (*annotations.Annotations, string, bool, error) {
+			return draft, "old-hash", true, nil
+		},
+		write: func(_ context.Context, _ string, a *annotations.Annotations, _ string) (string, error) {
+			written = a.Annotations
+			return "new-hash", nil
+		},
+	}
+
+	h := handler.New(rw, new(AnnotationsAPIMock), annotations.NewCanonicalizer(annotations.NewCanonicalAnnotationSorter), aug, time.Second)
+	r := vestigo.NewRouter()
+	r.Patch("/drafts/content/:uuid/annotations", h.PatchAnnotations)
+
+	// the read list is [about A, mentions B], so index 1 is the mentions of B
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newPatchRequest(`[
+		{"op":"test","path":"/annotations/1/id","value":"`+patchConceptB+`"},
+		{"op":"remove","path":"/annotations/1"}
+	]`))
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// the annotation with the unresolved concept is kept as it is
+	assert.Equal(t, []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+		{Predicate: patchAbout, ConceptId: patchConceptC},
+	}, written)
+}
+
+func TestUnhappyPatchAnnotations(t *testing.T) {
+	draft := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+	}}
+
+	tests := map[string]struct {
+		contentType    string
+		body           string
+		expectedStatus int
+	}{
+		"wrong content type": {
+			contentType:    "application/json",
+			body:           `[]`,
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+		"malformed body": {
+			contentType:    annotations.JSONPatchContentType,
+			body:           `[{"op":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"patch cannot be applied": {
+			contentType:    annotations.JSONPatchContentType,
+			body:           `[{"op":"remove","path":"/annotations/1"}]`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		"invalid predicate in result": {
+			contentType:    annotations.JSONPatchContentType,
+			body:           `[{"op":"replace","path":"/annotations/0/predicate","value":"http://www.ft.com/ontology/annotation/majorMentions"}]`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		"invalid concept ID in result": {
+			contentType:    annotations.JSONPatchContentType,
+			body:           `[{"op":"replace","path":"/annotations/0/id","value":"http://api.ft.com/things/9577c6d4-b09e-4552-b88f-e52745abe02b"}]`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rw := new(RWMock)
+			rw.On("Read", mock.Anything, patchContentUUID).Return(draft, "old-hash", true, nil)
+
+			r := newPatchRouter(rw, new(AnnotationsAPIMock))
+
+			req := newPatchRequest(test.body)
+			req.Header.Set("Content-Type", test.contentType)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+			resp := w.Result()
+			defer resp.Body.Close()
+			assert.Equal(t, test.expectedStatus, resp.StatusCode)
+			rw.AssertNotCalled(t, "Write", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestPatchAnnotationsReadsDraftWithoutReadRW(t *testing.T) {
+	draft := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+	}}
+	expected := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+		{Predicate: patchMentions, ConceptId: patchConceptB},
+	}}
+
+	rw := new(RWMock)
+	rw.On("Read", mock.Anything, patchContentUUID).Return(draft, "old-hash", true, nil)
+	rw.On("Write", mock.Anything, patchContentUUID, expected, "old-hash").Return("new-hash", nil).Once()
+	readRW := new(RWMock)
+	readRW.On("Read", mock.Anything, patchContentUUID).Return(draft, "stale-hash", true, nil)
+	aug := &AugmenterMock{
+		augment: func(ctx context.Context, depletedAnnotations []annotations.Annotation) ([]annotations.Annotation, error) {
+			return depletedAnnotations, nil
+		},
+	}
+	h := handler.New(rw, new(AnnotationsAPIMock), annotations.NewCanonicalizer(annotations.NewCanonicalAnnotationSorter), aug, time.Second, handler.WithReadRW(readRW))
+	r := vestigo.NewRouter()
+	r.Patch("/drafts/content/:uuid/annotations", h.PatchAnnotations)
+	r.Get("/drafts/content/:uuid/annotations", h.ReadAnnotations)
+
+	req := newPatchRequest(`[{"op":"add","path":"/annotations/-","value":{"predicate":"` + patchMentions + `","id":"` + patchConceptB + `"}}]`)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	readRW.AssertNotCalled(t, "Read", mock.Anything, mock.Anything)
+
+	req = httptest.NewRequest("GET", "/drafts/content/"+patchContentUUID+"/annotations", nil)
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	resp = w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "stale-hash", resp.Header.Get(annotations.DocumentHashHeader))
+
+	rw.AssertExpectations(t)
+	readRW.AssertExpectations(t)
+}
+
+func TestReadAnnotationsInPatchOrder(t *testing.T) {
+	draft := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchMentions, ConceptId: patchConceptB},
+		{Predicate: patchAbout, ConceptId: patchConceptC},
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+	}}
+
+	rw := new(RWMock)
+	rw.On("Read", mock.Anything, patchContentUUID).Return(draft, "old-hash", true, nil)
+	aug := &AugmenterMock{
+		augment: func(ctx context.Context, depletedAnnotations []annotations.Annotation) ([]annotations.Annotation, error) {
+			return depletedAnnotations, nil
+		},
+	}
+	h := handler.New(rw, new(AnnotationsAPIMock), annotations.NewCanonicalizer(annotations.NewCanonicalAnnotationSorter), aug, time.Second)
+	r := vestigo.NewRouter()
+	r.Get("/drafts/content/:uuid/annotations", h.ReadAnnotations)
+
+	req := httptest.NewRequest("GET", "/drafts/content/"+patchContentUUID+"/annotations", nil)
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	actual := annotations.Annotations{}
+	err := json.NewDecoder(resp.Body).Decode(&actual)
+	assert.NoError(t, err)
+	// the order the indexes of TestPatchAnnotations refer to
+	assert.Equal(t, []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+		{Predicate: patchAbout, ConceptId: patchConceptC},
+		{Predicate: patchMentions, ConceptId: patchConceptB},
+	}, actual.Annotations)
+
+	rw.AssertExpectations(t)
+}
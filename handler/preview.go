@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/Financial-Times/draft-annotations-api/annotations"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/husobee/vestigo"
+	log "github.com/sirupsen/logrus"
+)
+
+// PreviewAnnotations returns the annotations a content would be published with: its draft annotations, or the published
+// ones if there is no draft, with the predicates they are published with, together with the implicit annotations
+// UPP currently derives for the published version. The implicit annotations have the derived source.
+func (h *Handler) PreviewAnnotations(w http.ResponseWriter, r *http.Request) {
+	contentUUID := vestigo.Param(r, "uuid")
+	tID := tidutils.GetTransactionIDFromRequest(r)
+
+	ctx, cancel := context.WithTimeout(tidutils.TransactionAwareContext(r.Context(), tID), h.timeout)
+	defer cancel()
+
+	readLog := readLogEntry(ctx, contentUUID)
+
+	w.Header().Add("Content-Type", "application/json")
+
+	if err := validateUUID(contentUUID); err != nil {
+		writeMessage(w, "Invalid content UUID: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	preview, hash, aug, err := h.previewAnnotations(ctx, contentUUID, readLog)
+	if err != nil {
+		handleReadErrors(err, readLog, w)
+		return
+	}
+	if hash != "" {
+		w.Header().Set(annotations.DocumentHashHeader, hash)
+	}
+	if aug.degraded {
+		w.Header().Set(PartiallyAugmentedHeader, "true")
+	}
+
+	err = json.NewEncoder(w).Encode(&AnnotationsResponse{Annotations: preview, Unresolved: aug.unresolved})
+	if err != nil {
+		readLog.WithError(err).Error("Failed to encode response")
+		handleReadErrors(err, readLog, w)
+	}
+}
+
+func (h *Handler) previewAnnotations(ctx context.Context, contentUUID string, readLog *log.Entry) ([]annotations.Annotation, string, augmentation, error) {
+	result, hash, aug, err := h.readAnnotations(ctx, contentUUID, true, readLog)
+	if err != nil {
+		return nil, hash, augmentation{}, err
+	}
+	preview := switchToPublishedPredicates(result)
+
+	readLog.Info("Retrieving implicit annotations from UPP")
+	implicit, err := h.annotationsAPI.GetImplicit(ctx, contentUUID)
+	if err != nil {
+		var uppErr annotations.UPPError
+		if !errors.As(err, &uppErr) || uppErr.Status() != http.StatusNotFound {
+			return nil, hash, augmentation{}, err
+		}
+		readLog.Info("Implicit annotations not found, previewing the explicit annotations only")
+	}
+
+	preview = append(preview, implicit...)
+	sort.Sort(annotations.NewCanonicalAnnotationSorter(preview))
+	return preview, hash, aug, nil
+}
@@ -0,0 +1,122 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Financial-Times/draft-annotations-api/annotations"
+	"github.com/Financial-Times/draft-annotations-api/handler"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/husobee/vestigo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	previewBrand           = "http://www.ft.com/ontology/product/Brand"
+	previewImplicitlyAbout = "http://www.ft.com/ontology/implicitlyAbout"
+)
+
+func newPreviewRouter(rw *RWMock, annAPI *AnnotationsAPIMock) *vestigo.Router {
+	aug := &AugmenterMock{
+		augment: func(ctx context.Context, depletedAnnotations []annotations.Annotation) ([]annotations.Annotation, error) {
+			return depletedAnnotations, nil
+		},
+	}
+	h := handler.New(rw, annAPI, annotations.NewCanonicalizer(annotations.NewCanonicalAnnotationSorter), aug, time.Second)
+	r := vestigo.NewRouter()
+	r.Get("/drafts/content/:uuid/annotations/preview", h.PreviewAnnotations)
+	return r
+}
+
+func newPreviewRequest() *http.Request {
+	req := httptest.NewRequest("GET", "/drafts/content/"+patchContentUUID+"/annotations/preview", nil)
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	return req
+}
+
+func TestPreviewAnnotations(t *testing.T) {
+	draft := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+		{Predicate: "http://www.ft.com/ontology/classification/isClassifiedBy", ConceptId: patchConceptB, Type: previewBrand},
+	}}
+	implicit := []annotations.Annotation{
+		{Predicate: previewImplicitlyAbout, ConceptId: patchConceptC, PrefLabel: "Concept C", Source: annotations.SourceDerived},
+	}
+
+	rw := new(RWMock)
+	rw.On("Read", mock.Anything, patchContentUUID).Return(draft, "draft-hash", true, nil)
+	annAPI := new(AnnotationsAPIMock)
+	annAPI.On("GetImplicit", mock.Anything, patchContentUUID).Return(implicit, nil)
+
+	w := httptest.NewRecorder()
+	newPreviewRouter(rw, annAPI).ServeHTTP(w, newPreviewRequest())
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "draft-hash", resp.Header.Get(annotations.DocumentHashHeader))
+
+	actual := annotations.Annotations{}
+	err := json.NewDecoder(resp.Body).Decode(&actual)
+	assert.NoError(t, err)
+	assert.Equal(t, []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+		{Predicate: "http://www.ft.com/ontology/hasBrand", ConceptId: patchConceptB, Type: previewBrand},
+		{Predicate: previewImplicitlyAbout, ConceptId: patchConceptC, PrefLabel: "Concept C", Source: annotations.SourceDerived},
+	}, actual.Annotations)
+
+	rw.AssertExpectations(t)
+	annAPI.AssertExpectations(t)
+}
+
+func TestPreviewAnnotationsWithoutImplicitAnnotations(t *testing.T) {
+	draft := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+	}}
+
+	rw := new(RWMock)
+	rw.On("Read", mock.Anything, patchContentUUID).Return(draft, "draft-hash", true, nil)
+	annAPI := new(AnnotationsAPIMock)
+	annAPI.On("GetImplicit", mock.Anything, patchContentUUID).
+		Return([]annotations.Annotation(nil), annotations.NewUPPError(annotations.UPPNotFoundMsg, http.StatusNotFound, nil))
+
+	w := httptest.NewRecorder()
+	newPreviewRouter(rw, annAPI).ServeHTTP(w, newPreviewRequest())
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	actual := annotations.Annotations{}
+	err := json.NewDecoder(resp.Body).Decode(&actual)
+	assert.NoError(t, err)
+	assert.Equal(t, draft.Annotations, actual.Annotations)
+}
+
+func TestPreviewAnnotationsUPPUnavailable(t *testing.T) {
+	draft := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+	}}
+
+	rw := new(RWMock)
+	rw.On("Read", mock.Anything, patchContentUUID).Return(draft, "draft-hash", true, nil)
+	annAPI := new(AnnotationsAPIMock)
+	annAPI.On("GetImplicit", mock.Anything, patchContentUUID).
+		Return([]annotations.Annotation(nil), annotations.NewUPPError(annotations.UPPServiceUnavailableMsg, http.StatusServiceUnavailable, nil))
+
+	w := httptest.NewRecorder()
+	newPreviewRouter(rw, annAPI).ServeHTTP(w, newPreviewRequest())
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
+
+func TestPreviewAnnotationsInvalidUUID(t *testing.T) {
+	req := httptest.NewRequest("GET", "/drafts/content/not-a-uuid/annotations/preview", nil)
+	w := httptest.NewRecorder()
+	newPreviewRouter(new(RWMock), new(AnnotationsAPIMock)).ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
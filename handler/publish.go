@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Financial-Times/draft-annotations-api/annotations"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/husobee/vestigo"
+	log "github.com/sirupsen/logrus"
+)
+
+// Publisher interface encapsulates logic for sending annotations to the downstream publishing endpoint.
+type Publisher interface {
+	Publish(ctx context.Context, contentUUID string, annotations *annotations.Annotations, hash string) (int, error)
+}
+
+// PublishResponse is the body of the publish endpoint response.
+// PublishStatus is the status code returned by the downstream publishing endpoint.
+// Unresolved lists the concepts of the draft annotations which have not been published because their data has not been found.
+type PublishResponse struct {
+	Hash          string                          `json:"hash"`
+	PublishStatus int                             `json:"publishStatus"`
+	Annotations   []annotations.Annotation        `json:"annotations"`
+	Unresolved    []annotations.UnresolvedConcept `json:"unresolved,omitempty"`
+}
+
+// PublishAnnotations sends the current draft annotations for a given content uuid to the publishing endpoint,
+// converted to the predicates they are published with.
+// The annotations whose concept data has not been found cannot be published: they are left out and reported
+// as unresolved, or the publish is rejected if the strict concepts are enabled.
+func (h *Handler) PublishAnnotations(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	contentUUID := vestigo.Param(r, "uuid")
+
+	tID := tidutils.GetTransactionIDFromRequest(r)
+	ctx := tidutils.TransactionAwareContext(context.Background(), tID)
+	publishLog := log.WithField(tidutils.TransactionIDKey, tID).WithField("uuid", contentUUID)
+
+	if err := validateUUID(contentUUID); err != nil {
+		handleWriteErrors("Invalid content UUID", err, publishLog, w, http.StatusBadRequest)
+		return
+	}
+	if h.publisher == nil {
+		writeMessage(w, "Publishing draft annotations is not enabled", http.StatusNotImplemented)
+		return
+	}
+
+	publishLog.Debug("Reading draft from annotations RW...")
+	draft, hash, hasDraft, err := h.annotationsRW.Read(ctx, contentUUID)
+	if err != nil {
+		handleWriteErrors("Error reading draft annotations", err, publishLog, w, http.StatusInternalServerError)
+		return
+	}
+	if !hasDraft {
+		writeMessage(w, "There are no draft annotations to publish", http.StatusNotFound)
+		return
+	}
+
+	published, unresolved, err := h.annotationsAugmenter.AugmentAnnotations(ctx, draft.Annotations)
+	if err == nil && len(unresolved) > 0 && h.strictConcepts {
+		err = &unresolvedConceptsError{unresolved: unresolved}
+	}
+	if err != nil {
+		handleWriteErrors("Error augmenting draft annotations", err, publishLog, w, http.StatusInternalServerError)
+		return
+	}
+	if len(unresolved) > 0 {
+		publishLog.WithField("unresolved", len(unresolved)).Warn("Concept data not found for some annotations, publishing without them")
+	}
+	publishedAnnotations := &annotations.Annotations{Annotations: h.c14n.Canonicalize(switchToPublishedPredicates(published))}
+
+	publishLog.Info("Publishing draft annotations")
+	status, err := h.publisher.Publish(ctx, contentUUID, publishedAnnotations, hash)
+	if err != nil {
+		handlePublishErrors(err, publishLog, w)
+		return
+	}
+
+	w.Header().Set(annotations.DocumentHashHeader, hash)
+
+	err = json.NewEncoder(w).Encode(&PublishResponse{
+		Hash:          hash,
+		PublishStatus: status,
+		Annotations:   publishedAnnotations.Annotations,
+		Unresolved:    unresolved,
+	})
+	if err != nil {
+		handleWriteErrors("Error in encoding publish response", err, publishLog, w, http.StatusInternalServerError)
+	}
+}
+
+// handlePublishErrors forwards the client errors of the publishing endpoint,
+// and reports its other failures as a bad gateway.
+func handlePublishErrors(err error, publishLog *log.Entry, w http.ResponseWriter) {
+	var publishErr annotations.PublishError
+	if errors.As(err, &publishErr) && publishErr.Status() >= http.StatusBadRequest && publishErr.Status() < http.StatusInternalServerError {
+		publishLog.WithError(err).Warn("Publish endpoint rejected the draft annotations, forwarding its response back to client.")
+		w.WriteHeader(publishErr.Status())
+		w.Write(publishErr.Body())
+		return
+	}
+	handleWriteErrors("Error publishing draft annotations", err, publishLog, w, http.StatusBadGateway)
+}
@@ -0,0 +1,170 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Financial-Times/draft-annotations-api/annotations"
+	"github.com/Financial-Times/draft-annotations-api/handler"
+	"github.com/Financial-Times/draft-annotations-api/mapper"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/husobee/vestigo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const publishBrand = "http://www.ft.com/thing/dbb0bdae-1f0c-11e4-b0cb-b2227cce2b54"
+
+type PublisherMock struct {
+	mock.Mock
+}
+
+func (m *PublisherMock) Publish(ctx context.Context, contentUUID string, ann *annotations.Annotations, hash string) (int, error) {
+	args := m.Called(ctx, contentUUID, ann, hash)
+	return args.Int(0), args.Error(1)
+}
+
+func newPublishRouter(rw *RWMock, publisher handler.Publisher) *vestigo.Router {
+	aug := &AugmenterMock{
+		augment: func(ctx context.Context, depletedAnnotations []annotations.Annotation) ([]annotations.Annotation, error) {
+			augmented := make([]annotations.Annotation, len(depletedAnnotations))
+			for i, ann := range depletedAnnotations {
+				if ann.ConceptId == publishBrand {
+					ann.Type = mapper.ConceptTypeBrand
+				}
+				augmented[i] = ann
+			}
+			return augmented, nil
+		},
+	}
+	var opts []handler.Option
+	if publisher != nil {
+		opts = append(opts, handler.WithPublisher(publisher))
+	}
+	h := handler.New(rw, new(AnnotationsAPIMock), annotations.NewCanonicalizer(annotations.NewCanonicalAnnotationSorter), aug, time.Second, opts...)
+	r := vestigo.NewRouter()
+	r.Post("/drafts/content/:uuid/annotations/publish", h.PublishAnnotations)
+	return r
+}
+
+func newPublishRequest(contentUUID string) *http.Request {
+	req := httptest.NewRequest("POST", "/drafts/content/"+contentUUID+"/annotations/publish", nil)
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	return req
+}
+
+func TestPublishAnnotations(t *testing.T) {
+	draft := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+		{Predicate: mapper.PredicateIsClassifiedBy, ConceptId: publishBrand},
+	}}
+	published := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+		{Predicate: mapper.PredicateHasBrand, ConceptId: publishBrand},
+	}}
+
+	rw := new(RWMock)
+	rw.On("Read", mock.Anything, patchContentUUID).Return(draft, "draft-hash", true, nil)
+	publisher := new(PublisherMock)
+	publisher.On("Publish", mock.MatchedBy(func(ctx context.Context) bool {
+		tid, _ := tidutils.GetTransactionIDFromContext(ctx)
+		return tid == testTID
+	}), patchContentUUID, published, "draft-hash").Return(http.StatusAccepted, nil)
+
+	r := newPublishRouter(rw, publisher)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newPublishRequest(patchContentUUID))
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "draft-hash", resp.Header.Get(annotations.DocumentHashHeader))
+
+	actual := handler.PublishResponse{}
+	err := json.NewDecoder(resp.Body).Decode(&actual)
+	assert.NoError(t, err)
+	assert.Equal(t, handler.PublishResponse{
+		Hash:          "draft-hash",
+		PublishStatus: http.StatusAccepted,
+		Annotations:   published.Annotations,
+	}, actual)
+
+	rw.AssertExpectations(t)
+	publisher.AssertExpectations(t)
+}
+
+func TestPublishAnnotationsErrors(t *testing.T) {
+	draft := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+	}}
+
+	tests := map[string]struct {
+		contentUUID    string
+		disabled       bool
+		hasDraft       bool
+		readErr        error
+		publishErr     error
+		expectedStatus int
+		expectedBody   string
+	}{
+		"invalid content UUID": {
+			contentUUID:    "foo",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"publishing disabled": {
+			contentUUID:    patchContentUUID,
+			disabled:       true,
+			expectedStatus: http.StatusNotImplemented,
+		},
+		"no draft": {
+			contentUUID:    patchContentUUID,
+			expectedStatus: http.StatusNotFound,
+		},
+		"RW error": {
+			contentUUID:    patchContentUUID,
+			readErr:        errors.New("sorry something failed"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+		"publish rejected": {
+			contentUUID:    patchContentUUID,
+			hasDraft:       true,
+			publishErr:     annotations.NewPublishError(http.StatusUnprocessableEntity, []byte(`{"message":"invalid annotations"}`)),
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"message":"invalid annotations"}`,
+		},
+		"publish endpoint unavailable": {
+			contentUUID:    patchContentUUID,
+			hasDraft:       true,
+			publishErr:     annotations.NewPublishError(http.StatusServiceUnavailable, nil),
+			expectedStatus: http.StatusBadGateway,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rw := new(RWMock)
+			rw.On("Read", mock.Anything, test.contentUUID).Return(draft, "draft-hash", test.hasDraft, test.readErr)
+			publisher := new(PublisherMock)
+			publisher.On("Publish", mock.Anything, test.contentUUID, draft, "draft-hash").Return(0, test.publishErr)
+
+			var p handler.Publisher = publisher
+			if test.disabled {
+				p = nil
+			}
+			r := newPublishRouter(rw, p)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newPublishRequest(test.contentUUID))
+			resp := w.Result()
+			assert.Equal(t, test.expectedStatus, resp.StatusCode)
+			if test.expectedBody != "" {
+				assert.JSONEq(t, test.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
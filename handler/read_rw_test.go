@@ -0,0 +1,172 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Financial-Times/draft-annotations-api/annotations"
+	"github.com/Financial-Times/draft-annotations-api/handler"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/husobee/vestigo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestWritesDoNotUseReadRW checks that the writes read the current draft from the annotations RW,
+// never from the read RW, which may return a stale draft when it coalesces the reads.
+func TestWritesDoNotUseReadRW(t *testing.T) {
+	draft := &annotations.Annotations{Annotations: []annotations.Annotation{{Predicate: patchMentions, ConceptId: patchConceptB}}}
+	errPreconditionFailed := annotations.NewRWError(http.StatusPreconditionFailed, "", annotations.ErrPreconditionFailed)
+
+	tests := map[string]struct {
+		method         string
+		path           string
+		body           string
+		oldHash        string
+		expectedStatus int
+	}{
+		"write": {
+			method:         "PUT",
+			path:           "",
+			body:           `{"annotations":[{"predicate":"` + patchAbout + `","id":"` + patchConceptA + `"}]}`,
+			oldHash:        "hash-3",
+			expectedStatus: http.StatusOK,
+		},
+		"merge": {
+			method:         "PUT",
+			path:           "",
+			body:           `{"annotations":[{"predicate":"` + patchAbout + `","id":"` + patchConceptA + `"},{"predicate":"` + patchMentions + `","id":"` + patchConceptB + `"},{"predicate":"` + patchAbout + `","id":"` + patchConceptC + `"}]}`,
+			oldHash:        "hash-2",
+			expectedStatus: http.StatusOK,
+		},
+		"add": {
+			method:         "POST",
+			path:           "",
+			body:           `{"predicate":"` + patchAbout + `","id":"` + patchConceptA + `"}`,
+			oldHash:        "hash-3",
+			expectedStatus: http.StatusOK,
+		},
+		"replace": {
+			method:         "PATCH",
+			path:           "/838b3fbe-efbc-3cfe-b5c0-d38c046492a4",
+			body:           `{"id":"` + patchConceptA + `"}`,
+			oldHash:        "hash-3",
+			expectedStatus: http.StatusOK,
+		},
+		"delete concept": {
+			method:         "DELETE",
+			path:           "/838b3fbe-efbc-3cfe-b5c0-d38c046492a4",
+			oldHash:        "hash-3",
+			expectedStatus: http.StatusOK,
+		},
+		"patch": {
+			method:         "PATCH",
+			path:           "",
+			body:           `[{"op":"add","path":"/annotations/-","value":{"predicate":"` + patchAbout + `","id":"` + patchConceptA + `"}}]`,
+			oldHash:        "hash-3",
+			expectedStatus: http.StatusOK,
+		},
+		"discard": {
+			method:         "DELETE",
+			path:           "",
+			oldHash:        "hash-3",
+			expectedStatus: http.StatusNoContent,
+		},
+		"undo": {
+			method:         "POST",
+			path:           "/undo",
+			oldHash:        "hash-3",
+			expectedStatus: http.StatusOK,
+		},
+		"restore": {
+			method:         "POST",
+			path:           "/restore?hash=hash-1",
+			oldHash:        "hash-3",
+			expectedStatus: http.StatusOK,
+		},
+		"publish": {
+			method:         "POST",
+			path:           "/publish",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rw := &RWMock{
+				read: func(_ context.Context, _ string) (*annotations.Annotations, string, bool, error) {
+					return &annotations.Annotations{Annotations: draft.Annotations}, "hash-3", true, nil
+				},
+				write: func(_ context.Context, _ string, _ *annotations.Annotations, hash string) (string, error) {
+					if hash != "hash-3" {
+						return "", errPreconditionFailed
+					}
+					return "hash-4", nil
+				},
+				delete: func(_ context.Context, _ string, _ string) error {
+					return nil
+				},
+			}
+			readRW := &RWMock{
+				read: func(_ context.Context, _ string) (*annotations.Annotations, string, bool, error) {
+					t.Error("The current draft has been read with the read RW")
+					return nil, "", false, errors.New("unexpected read")
+				},
+			}
+			annAPI := &AnnotationsAPIMock{
+				getAll: func(_ context.Context, _ string) ([]annotations.Annotation, error) {
+					return draft.Annotations, nil
+				},
+				getAllButV2: func(_ context.Context, _ string) ([]annotations.Annotation, error) {
+					return draft.Annotations, nil
+				},
+			}
+			aug := &AugmenterMock{
+				augment: func(_ context.Context, depletedAnnotations []annotations.Annotation) ([]annotations.Annotation, error) {
+					return depletedAnnotations, nil
+				},
+				augmentOrDegrade: func(_ context.Context, depletedAnnotations []annotations.Annotation) ([]annotations.Annotation, bool) {
+					return depletedAnnotations, false
+				},
+			}
+			publisher := new(PublisherMock)
+			publisher.On("Publish", mock.Anything, patchContentUUID, mock.Anything, "hash-3").Return(http.StatusAccepted, nil).Maybe()
+
+			h := handler.New(rw, annAPI, annotations.NewCanonicalizer(annotations.NewCanonicalAnnotationSorter), aug, time.Second,
+				handler.WithReadRW(readRW),
+				handler.WithHistory(newUndoHistory(t)),
+				handler.WithPublisher(publisher),
+			)
+			r := vestigo.NewRouter()
+			r.Post("/drafts/content/:uuid/annotations/undo", h.UndoAnnotations)
+			r.Post("/drafts/content/:uuid/annotations/restore", h.RestoreAnnotations)
+			r.Post("/drafts/content/:uuid/annotations/publish", h.PublishAnnotations)
+			r.Put("/drafts/content/:uuid/annotations", h.WriteAnnotations)
+			r.Post("/drafts/content/:uuid/annotations", h.AddAnnotation)
+			r.Patch("/drafts/content/:uuid/annotations", h.PatchAnnotations)
+			r.Delete("/drafts/content/:uuid/annotations", h.DiscardDraftAnnotations)
+			r.Delete("/drafts/content/:uuid/annotations/:cuuid", h.DeleteAnnotation)
+			r.Patch("/drafts/content/:uuid/annotations/:cuuid", h.ReplaceAnnotation)
+
+			req := httptest.NewRequest(test.method, "/drafts/content/"+patchContentUUID+"/annotations"+test.path, strings.NewReader(test.body))
+			req.Header.Set(tidutils.TransactionIDHeader, testTID)
+			if name == "patch" {
+				req.Header.Set("Content-Type", annotations.JSONPatchContentType)
+			}
+			if test.oldHash != "" {
+				req.Header.Set(annotations.PreviousDocumentHashHeader, test.oldHash)
+			}
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+			resp := w.Result()
+			defer resp.Body.Close()
+			assert.Equal(t, test.expectedStatus, resp.StatusCode)
+		})
+	}
+}
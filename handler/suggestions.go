@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Financial-Times/draft-annotations-api/annotations"
+	log "github.com/sirupsen/logrus"
+)
+
+// readSuggestions returns the augmented suggested annotations for the given content whose concepts
+// are not in the given annotations, so that editors are only offered the concepts they have not annotated yet.
+// It also reports whether the suggested annotations have been partially augmented.
+func (h *Handler) readSuggestions(ctx context.Context, contentUUID string, current []annotations.Annotation, showHasBrand bool, readLog *log.Entry) ([]annotations.Annotation, bool, error) {
+	readLog.Info("Retrieving suggested annotations")
+	suggestions, err := h.suggestionsAPI.GetSuggestions(ctx, contentUUID)
+	if err != nil {
+		readLog.WithError(err).Error("Failed to retrieve suggested annotations")
+		return nil, false, err
+	}
+	if len(suggestions) == 0 {
+		return suggestions, false, nil
+	}
+
+	suggestions, aug, err := h.augmentForRead(ctx, suggestions, showHasBrand, readLog)
+	if err != nil {
+		return nil, false, err
+	}
+
+	annotated := make(map[string]struct{}, len(current))
+	for _, ann := range current {
+		annotated[ann.ConceptId] = struct{}{}
+	}
+
+	newSuggestions := make([]annotations.Annotation, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		if _, found := annotated[suggestion.ConceptId]; found {
+			continue
+		}
+		newSuggestions = append(newSuggestions, suggestion)
+	}
+	return newSuggestions, aug.degraded, nil
+}
+
+func includeSuggestionsParam(r *http.Request) (bool, error) {
+	queryParam := r.URL.Query().Get("includeSuggestions")
+	if queryParam == "" {
+		return false, nil
+	}
+	includeSuggestions, err := strconv.ParseBool(queryParam)
+	if err != nil {
+		return false, fmt.Errorf("invalid param includeSuggestions: %s ", queryParam)
+	}
+	return includeSuggestions, nil
+}
@@ -0,0 +1,135 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Financial-Times/draft-annotations-api/annotations"
+	"github.com/Financial-Times/draft-annotations-api/handler"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/husobee/vestigo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type SuggestionsAPIMock struct {
+	mock.Mock
+}
+
+func (m *SuggestionsAPIMock) GetSuggestions(ctx context.Context, contentUUID string) ([]annotations.Annotation, error) {
+	args := m.Called(ctx, contentUUID)
+	return args.Get(0).([]annotations.Annotation), args.Error(1)
+}
+
+func newSuggestionsRouter(rw *RWMock, opts ...handler.Option) *vestigo.Router {
+	aug := &AugmenterMock{
+		augment: func(ctx context.Context, depletedAnnotations []annotations.Annotation) ([]annotations.Annotation, error) {
+			augmented := make([]annotations.Annotation, 0, len(depletedAnnotations))
+			for _, ann := range depletedAnnotations {
+				ann.PrefLabel = diffPrefLabels[ann.ConceptId]
+				augmented = append(augmented, ann)
+			}
+			return augmented, nil
+		},
+	}
+	h := handler.New(rw, new(AnnotationsAPIMock), nil, aug, time.Second, opts...)
+	r := vestigo.NewRouter()
+	r.Get("/drafts/content/:uuid/annotations", h.ReadAnnotations)
+	return r
+}
+
+func newSuggestionsRequest(includeSuggestions string) *http.Request {
+	req := httptest.NewRequest("GET", "/drafts/content/"+patchContentUUID+"/annotations?includeSuggestions="+includeSuggestions, nil)
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	return req
+}
+
+func TestReadAnnotationsIncludeSuggestions(t *testing.T) {
+	draft := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+	}}
+	suggestions := []annotations.Annotation{
+		{Predicate: patchMentions, ConceptId: patchConceptA, Source: annotations.SourceSuggestion, ConfidenceScore: score(0.6)},
+		{Predicate: patchMentions, ConceptId: patchConceptB, Source: annotations.SourceSuggestion, ConfidenceScore: score(0.9)},
+		{Predicate: patchAbout, ConceptId: patchConceptC, Source: annotations.SourceSuggestion},
+	}
+
+	rw := new(RWMock)
+	rw.On("Read", mock.Anything, patchContentUUID).Return(draft, "draft-hash", true, nil)
+	suggestionsAPI := new(SuggestionsAPIMock)
+	suggestionsAPI.On("GetSuggestions", mock.Anything, patchContentUUID).Return(suggestions, nil)
+
+	w := httptest.NewRecorder()
+	newSuggestionsRouter(rw, handler.WithSuggestions(suggestionsAPI)).ServeHTTP(w, newSuggestionsRequest("true"))
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "draft-hash", resp.Header.Get(annotations.DocumentHashHeader))
+
+	actual := annotations.Annotations{}
+	err := json.NewDecoder(resp.Body).Decode(&actual)
+	assert.NoError(t, err)
+	assert.Equal(t, []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA, PrefLabel: "Concept A"},
+		{Predicate: patchMentions, ConceptId: patchConceptB, PrefLabel: "Concept B", Source: annotations.SourceSuggestion, ConfidenceScore: score(0.9)},
+		{Predicate: patchAbout, ConceptId: patchConceptC, PrefLabel: "Concept C", Source: annotations.SourceSuggestion},
+	}, actual.Annotations)
+
+	rw.AssertExpectations(t)
+	suggestionsAPI.AssertExpectations(t)
+}
+
+func TestReadAnnotationsWithoutSuggestions(t *testing.T) {
+	draft := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+	}}
+
+	rw := new(RWMock)
+	rw.On("Read", mock.Anything, patchContentUUID).Return(draft, "draft-hash", true, nil)
+	suggestionsAPI := new(SuggestionsAPIMock)
+
+	w := httptest.NewRecorder()
+	newSuggestionsRouter(rw, handler.WithSuggestions(suggestionsAPI)).ServeHTTP(w, newSuggestionsRequest("false"))
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	actual := annotations.Annotations{}
+	err := json.NewDecoder(resp.Body).Decode(&actual)
+	assert.NoError(t, err)
+	assert.Len(t, actual.Annotations, 1)
+
+	suggestionsAPI.AssertNotCalled(t, "GetSuggestions", mock.Anything, mock.Anything)
+}
+
+func TestReadAnnotationsSuggestionsUnavailable(t *testing.T) {
+	draft := &annotations.Annotations{Annotations: []annotations.Annotation{
+		{Predicate: patchAbout, ConceptId: patchConceptA},
+	}}
+
+	rw := new(RWMock)
+	rw.On("Read", mock.Anything, patchContentUUID).Return(draft, "draft-hash", true, nil)
+	suggestionsAPI := new(SuggestionsAPIMock)
+	suggestionsAPI.On("GetSuggestions", mock.Anything, patchContentUUID).
+		Return([]annotations.Annotation(nil), annotations.NewUPPError(annotations.UPPServiceUnavailableMsg, http.StatusServiceUnavailable, nil))
+
+	w := httptest.NewRecorder()
+	newSuggestionsRouter(rw, handler.WithSuggestions(suggestionsAPI)).ServeHTTP(w, newSuggestionsRequest("true"))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestReadAnnotationsSuggestionsNotEnabled(t *testing.T) {
+	w := httptest.NewRecorder()
+	newSuggestionsRouter(new(RWMock)).ServeHTTP(w, newSuggestionsRequest("true"))
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+}
+
+func TestReadAnnotationsInvalidIncludeSuggestions(t *testing.T) {
+	w := httptest.NewRecorder()
+	newSuggestionsRouter(new(RWMock), handler.WithSuggestions(new(SuggestionsAPIMock))).ServeHTTP(w, newSuggestionsRequest("maybe"))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/Financial-Times/draft-annotations-api/annotations"
+	log "github.com/sirupsen/logrus"
+)
+
+// AnnotationsResponse is the body of the responses returning annotations.
+// Unresolved lists the concepts of the annotations whose concept data has not been found.
+type AnnotationsResponse struct {
+	Annotations []annotations.Annotation        `json:"annotations"`
+	Unresolved  []annotations.UnresolvedConcept `json:"unresolved,omitempty"`
+}
+
+// UnresolvedConceptsResponse is the body of the response returned when the written annotations
+// have concepts whose data has not been found, with the strict concepts enabled.
+type UnresolvedConceptsResponse struct {
+	Message    string                          `json:"message"`
+	Unresolved []annotations.UnresolvedConcept `json:"unresolved"`
+}
+
+// unresolvedConceptsError is returned when the annotations to write have concepts whose data has not been found,
+// with the strict concepts enabled.
+type unresolvedConceptsError struct {
+	unresolved []annotations.UnresolvedConcept
+}
+
+func (e *unresolvedConceptsError) Error() string {
+	return fmt.Sprintf("concept data not found for %d concept(s)", len(e.unresolved))
+}
+
+// augmentation reports how the annotations of a read have been augmented: the concepts whose data has not been found
+// and whether the annotations have been partially augmented.
+type augmentation struct {
+	unresolved []annotations.UnresolvedConcept
+	degraded   bool
+}
+
+func writeUnresolvedConcepts(w http.ResponseWriter, msg string, unresolvedErr *unresolvedConceptsError) {
+	w.WriteHeader(http.StatusUnprocessableEntity)
+
+	err := json.NewEncoder(w).Encode(&UnresolvedConceptsResponse{
+		Message:    msg,
+		Unresolved: unresolvedErr.unresolved,
+	})
+	if err != nil {
+		log.WithError(err).Error("Failed to encode unresolved concepts response.")
+	}
+}
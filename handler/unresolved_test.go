@@ -0,0 +1,213 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Financial-Times/draft-annotations-api/annotations"
+	"github.com/Financial-Times/draft-annotations-api/handler"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/husobee/vestigo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const unresolvedConceptID = "http://www.ft.com/thing/9577c6d4-b09e-4552-b88f-e52745abe02b"
+
+var testUnresolved = []annotations.UnresolvedConcept{
+	{ConceptId: unresolvedConceptID, Predicates: []string{"http://www.ft.com/ontology/annotation/about"}},
+}
+
+// unresolvedTestAnnotations returns annotations with one annotated with the unresolved concept.
+func unresolvedTestAnnotations() []annotations.Annotation {
+	return []annotations.Annotation{
+		{
+			Predicate: "http://www.ft.com/ontology/annotation/mentions",
+			ConceptId: "http://www.ft.com/thing/0a619d71-9af5-3755-90dd-f789b686c67a",
+			ApiUrl:    "http://api.ft.com/people/0a619d71-9af5-3755-90dd-f789b686c67a",
+			Type:      "http://www.ft.com/ontology/person/Person",
+			PrefLabel: "Barack H. Obama",
+		},
+		{
+			Predicate: "http://www.ft.com/ontology/annotation/about",
+			ConceptId: unresolvedConceptID,
+		},
+	}
+}
+
+// resolvedAnnotations returns the annotations of unresolvedTestAnnotations but the one with the unresolved concept.
+func resolvedAnnotations() []annotations.Annotation {
+	return unresolvedTestAnnotations()[:1]
+}
+
+func newUnresolvedWriteRequest(t *testing.T) *http.Request {
+	entity := bytes.Buffer{}
+	err := json.NewEncoder(&entity).Encode(&annotations.Annotations{Annotations: unresolvedTestAnnotations()})
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest("PUT", "http://api.ft.com/drafts/content/83a201c6-60cd-11e7-91a7-502f7ee26895/annotations", &entity)
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	return req
+}
+
+func TestWriteAnnotationsWithUnresolvedConcepts(t *testing.T) {
+	var written []annotations.Annotation
+	rw := &RWMock{
+		write: func(_ context.Context, _ string, a *annotations.Annotations, _ string) (string, error) {
+			written = a.Annotations
+			return "a-new-hash", nil
+		},
+	}
+	aug := &AugmenterMock{
+		augment: func(_ context.Context, _ []annotations.Annotation) ([]annotations.Annotation, error) {
+			return resolvedAnnotations(), nil
+		},
+		unresolved: testUnresolved,
+	}
+
+	canonicalizer := annotations.NewCanonicalizer(annotations.NewCanonicalAnnotationSorter)
+	h := handler.New(rw, new(AnnotationsAPIMock), canonicalizer, aug, time.Second)
+	r := vestigo.NewRouter()
+	r.Put("/drafts/content/:uuid/annotations", h.WriteAnnotations)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newUnresolvedWriteRequest(t))
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "a-new-hash", resp.Header.Get(annotations.DocumentHashHeader))
+
+	actual := handler.AnnotationsResponse{}
+	err := json.NewDecoder(resp.Body).Decode(&actual)
+	assert.NoError(t, err)
+	assert.Equal(t, testUnresolved, actual.Unresolved)
+
+	// the annotation with the unresolved concept is saved, not dropped
+	assert.Len(t, written, 2)
+	assert.Contains(t, conceptIDs(written), unresolvedConceptID)
+	assert.Equal(t, written, actual.Annotations)
+}
+
+func TestWriteAnnotationsWithUnresolvedConceptsStrict(t *testing.T) {
+	rw := new(RWMock)
+	aug := &AugmenterMock{
+		augment: func(_ context.Context, _ []annotations.Annotation) ([]annotations.Annotation, error) {
+			return resolvedAnnotations(), nil
+		},
+		unresolved: testUnresolved,
+	}
+
+	canonicalizer := annotations.NewCanonicalizer(annotations.NewCanonicalAnnotationSorter)
+	h := handler.New(rw, new(AnnotationsAPIMock), canonicalizer, aug, time.Second, handler.WithStrictConcepts())
+	r := vestigo.NewRouter()
+	r.Put("/drafts/content/:uuid/annotations", h.WriteAnnotations)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newUnresolvedWriteRequest(t))
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get(annotations.DocumentHashHeader))
+
+	actual := handler.UnresolvedConceptsResponse{}
+	err := json.NewDecoder(resp.Body).Decode(&actual)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, actual.Message)
+	assert.Equal(t, testUnresolved, actual.Unresolved)
+
+	rw.AssertNotCalled(t, "Write", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReadAnnotationsWithUnresolvedConcepts(t *testing.T) {
+	rw := new(RWMock)
+	rw.On("Read", mock.Anything, "83a201c6-60cd-11e7-91a7-502f7ee26895").Return(&annotations.Annotations{Annotations: unresolvedTestAnnotations()}, "a-hash", true, nil)
+	aug := &AugmenterMock{unresolved: testUnresolved}
+	aug.On("AugmentAnnotations", mock.Anything, unresolvedTestAnnotations()).Return(resolvedAnnotations(), nil)
+
+	h := handler.New(rw, new(AnnotationsAPIMock), nil, aug, time.Second)
+	r := vestigo.NewRouter()
+	r.Get("/drafts/content/:uuid/annotations", h.ReadAnnotations)
+
+	req := httptest.NewRequest("GET", "http://api.ft.com/drafts/content/83a201c6-60cd-11e7-91a7-502f7ee26895/annotations?sendHasBrand=true", nil)
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+	resp := w.Result()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	actual := handler.AnnotationsResponse{}
+	err := json.NewDecoder(resp.Body).Decode(&actual)
+	assert.NoError(t, err)
+	assert.Equal(t, resolvedAnnotations(), actual.Annotations)
+	assert.Equal(t, testUnresolved, actual.Unresolved)
+
+	rw.AssertExpectations(t)
+	aug.AssertExpectations(t)
+}
+
+func conceptIDs(list []annotations.Annotation) []string {
+	ids := make([]string, 0, len(list))
+	for _, ann := range list {
+		ids = append(ids, ann.ConceptId)
+	}
+	return ids
+}
+
+func TestPublishAnnotationsWithUnresolvedConcepts(t *testing.T) {
+	tests := map[string]struct {
+		opts           []handler.Option
+		expectedStatus int
+	}{
+		"lenient": {expectedStatus: http.StatusOK},
+		"strict":  {opts: []handler.Option{handler.WithStrictConcepts()}, expectedStatus: http.StatusUnprocessableEntity},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rw := new(RWMock)
+			rw.On("Read", mock.Anything, patchContentUUID).Return(&annotations.Annotations{Annotations: unresolvedTestAnnotations()}, "draft-hash", true, nil)
+			aug := &AugmenterMock{
+				augment: func(_ context.Context, _ []annotations.Annotation) ([]annotations.Annotation, error) {
+					return resolvedAnnotations(), nil
+				},
+				unresolved: testUnresolved,
+			}
+			publisher := new(PublisherMock)
+			published := &annotations.Annotations{Annotations: []annotations.Annotation{
+				{Predicate: "http://www.ft.com/ontology/annotation/mentions", ConceptId: "http://www.ft.com/thing/0a619d71-9af5-3755-90dd-f789b686c67a"},
+			}}
+			publisher.On("Publish", mock.Anything, patchContentUUID, published, "draft-hash").Return(http.StatusAccepted, nil)
+
+			canonicalizer := annotations.NewCanonicalizer(annotations.NewCanonicalAnnotationSorter)
+			opts := append([]handler.Option{handler.WithPublisher(publisher)}, test.opts...)
+			h := handler.New(rw, new(AnnotationsAPIMock), canonicalizer, aug, time.Second, opts...)
+			r := vestigo.NewRouter()
+			r.Post("/drafts/content/:uuid/annotations/publish", h.PublishAnnotations)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newPublishRequest(patchContentUUID))
+			resp := w.Result()
+			defer resp.Body.Close()
+			assert.Equal(t, test.expectedStatus, resp.StatusCode)
+
+			actual := handler.PublishResponse{}
+			err := json.NewDecoder(resp.Body).Decode(&actual)
+			assert.NoError(t, err)
+			assert.Equal(t, testUnresolved, actual.Unresolved)
+
+			if test.expectedStatus == http.StatusOK {
+				assert.Equal(t, published.Annotations, actual.Annotations)
+				publisher.AssertExpectations(t)
+			} else {
+				publisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package history
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Financial-Times/draft-annotations-api/internal/jsonl"
+)
+
+type fileStore struct {
+	sync.Mutex
+	dir         string
+	maxVersions int
+}
+
+// NewFileStore returns a Store keeping up to maxVersions versions per content in a JSON lines file
+// in the given directory, which is created if it does not exist. A maxVersions value of zero means no limit.
+// Once a content has maxVersions versions, its file is rewritten without the oldest one for each new version.
+func NewFileStore(dir string, maxVersions int) (Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating history directory: %w", err)
+	}
+	return &fileStore{dir: dir, maxVersions: maxVersions}, nil
+}
+
+func (s *fileStore) Append(_ context.Context, contentUUID string, version Version) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.maxVersions > 0 {
+		versions, err := s.read(contentUUID)
+		if err != nil {
+			return err
+		}
+		if len(versions) >= s.maxVersions {
+			return jsonl.Write(s.path(contentUUID), trim(append(versions, version), s.maxVersions))
+		}
+	}
+	return jsonl.Append(s.path(contentUUID), version)
+}
+
+func (s *fileStore) List(_ context.Context, contentUUID string) ([]Version, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.read(contentUUID)
+}
+
+func (s *fileStore) read(contentUUID string) ([]Version, error) {
+	versions, err := jsonl.Read[Version](s.path(contentUUID))
+	if err != nil {
+		return nil, fmt.Errorf("reading history of %s: %w", contentUUID, err)
+	}
+	return versions, nil
+}
+
+// path returns the file holding the versions of the given content.
+// Only the base name of the content UUID is used, so that it cannot point outside of the store directory.
+func (s *fileStore) path(contentUUID string) string {
+	return filepath.Join(s.dir, filepath.Base(contentUUID)+".jsonl")
+}
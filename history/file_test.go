@@ -0,0 +1,49 @@
+package history
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileStore(t *testing.T) {
+	s, err := NewFileStore(filepath.Join(t.TempDir(), "history"), 0)
+	assert.NoError(t, err)
+	testStore(t, s)
+}
+
+func TestBoundedFileStore(t *testing.T) {
+	s, err := NewFileStore(t.TempDir(), 2)
+	assert.NoError(t, err)
+	testBoundedStore(t, s)
+}
+
+func TestFileStorePersistsVersions(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	s, err := NewFileStore(dir, 0)
+	assert.NoError(t, err)
+	err = s.Append(ctx, testContentUUID, testVersions()[0])
+	assert.NoError(t, err)
+
+	reopened, err := NewFileStore(dir, 0)
+	assert.NoError(t, err)
+	versions, err := reopened.List(ctx, testContentUUID)
+	assert.NoError(t, err)
+	assert.Equal(t, testVersions()[:1], versions)
+}
+
+func TestFileStoreCorruptedFile(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, testContentUUID+".jsonl"), []byte("not json\n"), 0o644)
+	assert.NoError(t, err)
+
+	s, err := NewFileStore(dir, 0)
+	assert.NoError(t, err)
+	_, err = s.List(context.Background(), testContentUUID)
+	assert.Error(t, err)
+}
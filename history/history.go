@@ -0,0 +1,63 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Financial-Times/draft-annotations-api/annotations"
+)
+
+// ErrVersionNotFound is returned when there is no version of the draft annotations matching the request.
+var ErrVersionNotFound = errors.New("draft annotations version not found")
+
+// Version is a saved version of the draft annotations of a content.
+// Parent is the hash of the version that undoing this one goes back to,
+// and RestoredFrom the hash of the version it has been restored from, if any.
+type Version struct {
+	Hash          string                   `json:"hash"`
+	Timestamp     time.Time                `json:"timestamp"`
+	TransactionID string                   `json:"transactionId"`
+	Parent        string                   `json:"parent,omitempty"`
+	RestoredFrom  string                   `json:"restoredFrom,omitempty"`
+	Annotations   []annotations.Annotation `json:"annotations,omitempty"`
+}
+
+// Store records the versions of the draft annotations of each content.
+// Stores can be bounded, in which case the oldest versions of a content are dropped.
+type Store interface {
+	// Append records a new version of the draft annotations of the given content.
+	Append(ctx context.Context, contentUUID string, version Version) error
+	// List returns the recorded versions of the draft annotations of the given content, oldest first.
+	List(ctx context.Context, contentUUID string) ([]Version, error)
+}
+
+// FindByHash returns the version with the given hash, or ErrVersionNotFound.
+// If the same hash has been saved more than once the most recent version is returned.
+func FindByHash(versions []Version, hash string) (Version, error) {
+	for i := len(versions) - 1; i >= 0; i-- {
+		if versions[i].Hash == hash {
+			return versions[i], nil
+		}
+	}
+	return Version{}, ErrVersionNotFound
+}
+
+// FindAsOf returns the version that was current at the given time, i.e. the most recent version saved
+// at or before it, or ErrVersionNotFound if no version had been saved yet.
+func FindAsOf(versions []Version, t time.Time) (Version, error) {
+	for i := len(versions) - 1; i >= 0; i-- {
+		if !versions[i].Timestamp.After(t) {
+			return versions[i], nil
+		}
+	}
+	return Version{}, ErrVersionNotFound
+}
+
+// trim returns the most recent maxVersions versions, or all of them if maxVersions is not positive.
+func trim(versions []Version, maxVersions int) []Version {
+	if maxVersions <= 0 || len(versions) <= maxVersions {
+		return versions
+	}
+	return versions[len(versions)-maxVersions:]
+}
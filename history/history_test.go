@@ -0,0 +1,118 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Financial-Times/draft-annotations-api/annotations"
+	"github.com/stretchr/testify/assert"
+)
+
+const testContentUUID = "83a201c6-60cd-11e7-91a7-502f7ee26895"
+
+var testTime = time.Date(2023, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+func testVersions() []Version {
+	return []Version{
+		{
+			Hash:          "hash-1",
+			Timestamp:     testTime,
+			TransactionID: "tid_1",
+			Annotations: []annotations.Annotation{
+				{Predicate: "http://www.ft.com/ontology/annotation/about", ConceptId: "http://www.ft.com/thing/0a619d71-9af5-3755-90dd-f789b686c67a"},
+			},
+		},
+		{
+			Hash:          "hash-2",
+			Timestamp:     testTime.Add(time.Hour),
+			TransactionID: "tid_2",
+			Annotations: []annotations.Annotation{
+				{Predicate: "http://www.ft.com/ontology/annotation/mentions", ConceptId: "http://www.ft.com/thing/0a619d71-9af5-3755-90dd-f789b686c67a"},
+			},
+		},
+		{
+			Hash:          "hash-1",
+			Timestamp:     testTime.Add(2 * time.Hour),
+			TransactionID: "tid_3",
+			Annotations: []annotations.Annotation{
+				{Predicate: "http://www.ft.com/ontology/annotation/about", ConceptId: "http://www.ft.com/thing/0a619d71-9af5-3755-90dd-f789b686c67a"},
+			},
+		},
+	}
+}
+
+func TestFindByHash(t *testing.T) {
+	versions := testVersions()
+
+	v, err := FindByHash(versions, "hash-2")
+	assert.NoError(t, err)
+	assert.Equal(t, versions[1], v)
+
+	v, err = FindByHash(versions, "hash-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "tid_3", v.TransactionID)
+
+	_, err = FindByHash(versions, "hash-3")
+	assert.True(t, errors.Is(err, ErrVersionNotFound))
+}
+
+func TestFindAsOf(t *testing.T) {
+	versions := testVersions()
+
+	v, err := FindAsOf(versions, testTime.Add(90*time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, "tid_2", v.TransactionID)
+
+	v, err = FindAsOf(versions, testTime.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, "tid_2", v.TransactionID)
+
+	v, err = FindAsOf(versions, testTime.Add(24*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, "tid_3", v.TransactionID)
+
+	_, err = FindAsOf(versions, testTime.Add(-time.Second))
+	assert.True(t, errors.Is(err, ErrVersionNotFound))
+}
+
+// testStore checks the behaviour shared by all Store implementations.
+func testStore(t *testing.T, s Store) {
+	ctx := context.Background()
+
+	versions, err := s.List(ctx, testContentUUID)
+	assert.NoError(t, err)
+	assert.Empty(t, versions)
+
+	for _, v := range testVersions() {
+		err = s.Append(ctx, testContentUUID, v)
+		assert.NoError(t, err)
+	}
+	err = s.Append(ctx, "another-content", Version{Hash: "another-hash", Timestamp: testTime})
+	assert.NoError(t, err)
+
+	versions, err = s.List(ctx, testContentUUID)
+	assert.NoError(t, err)
+	assert.Equal(t, testVersions(), versions)
+}
+
+// testBoundedStore checks that a Store limited to two versions per content drops the oldest ones.
+func testBoundedStore(t *testing.T, s Store) {
+	ctx := context.Background()
+
+	for _, v := range testVersions() {
+		err := s.Append(ctx, testContentUUID, v)
+		assert.NoError(t, err)
+	}
+	err := s.Append(ctx, "another-content", Version{Hash: "another-hash", Timestamp: testTime})
+	assert.NoError(t, err)
+
+	versions, err := s.List(ctx, testContentUUID)
+	assert.NoError(t, err)
+	assert.Equal(t, testVersions()[1:], versions)
+
+	versions, err = s.List(ctx, "another-content")
+	assert.NoError(t, err)
+	assert.Len(t, versions, 1)
+}
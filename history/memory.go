@@ -0,0 +1,35 @@
+package history
+
+import (
+	"context"
+	"sync"
+)
+
+type memoryStore struct {
+	sync.RWMutex
+	versions    map[string][]Version
+	maxVersions int
+}
+
+// NewMemoryStore returns a Store keeping up to maxVersions versions per content in memory,
+// which are lost when the service restarts. A maxVersions value of zero means no limit.
+func NewMemoryStore(maxVersions int) Store {
+	return &memoryStore{versions: make(map[string][]Version), maxVersions: maxVersions}
+}
+
+func (s *memoryStore) Append(_ context.Context, contentUUID string, version Version) error {
+	s.Lock()
+	defer s.Unlock()
+
+	s.versions[contentUUID] = trim(append(s.versions[contentUUID], version), s.maxVersions)
+	return nil
+}
+
+func (s *memoryStore) List(_ context.Context, contentUUID string) ([]Version, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	versions := make([]Version, len(s.versions[contentUUID]))
+	copy(versions, s.versions[contentUUID])
+	return versions, nil
+}
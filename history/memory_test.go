@@ -0,0 +1,31 @@
+package history
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore(0))
+}
+
+func TestMemoryStoreListReturnsCopy(t *testing.T) {
+	s := NewMemoryStore(0)
+	ctx := context.Background()
+	err := s.Append(ctx, testContentUUID, Version{Hash: "hash-1"})
+	assert.NoError(t, err)
+
+	versions, err := s.List(ctx, testContentUUID)
+	assert.NoError(t, err)
+	versions[0].Hash = "changed"
+
+	versions, err = s.List(ctx, testContentUUID)
+	assert.NoError(t, err)
+	assert.Equal(t, "hash-1", versions[0].Hash)
+}
+
+func TestBoundedMemoryStore(t *testing.T) {
+	testBoundedStore(t, NewMemoryStore(2))
+}
@@ -0,0 +1,56 @@
+package jsonl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testValue struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func TestAppendAndRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "values.jsonl")
+
+	values, err := Read[testValue](path)
+	assert.NoError(t, err)
+	assert.Empty(t, values)
+	assert.NotNil(t, values)
+
+	for _, v := range []testValue{{Name: "a", Count: 1}, {Name: "b", Count: 2}} {
+		err = Append(path, v)
+		assert.NoError(t, err)
+	}
+
+	values, err = Read[testValue](path)
+	assert.NoError(t, err)
+	assert.Equal(t, []testValue{{Name: "a", Count: 1}, {Name: "b", Count: 2}}, values)
+}
+
+func TestWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "values.jsonl")
+	err := Append(path, testValue{Name: "a", Count: 1})
+	assert.NoError(t, err)
+
+	err = Write(path, []testValue{{Name: "b", Count: 2}, {Name: "c", Count: 3}})
+	assert.NoError(t, err)
+
+	values, err := Read[testValue](path)
+	assert.NoError(t, err)
+	assert.Equal(t, []testValue{{Name: "b", Count: 2}, {Name: "c", Count: 3}}, values)
+	_, err = os.Stat(path + ".tmp")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestReadCorruptedLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "values.jsonl")
+	err := os.WriteFile(path, []byte("{\"name\":\"a\",\"count\":1}\nnot json\n"), 0o644)
+	assert.NoError(t, err)
+
+	_, err = Read[testValue](path)
+	assert.ErrorContains(t, err, "line 2")
+}
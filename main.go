@@ -1,16 +1,25 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	api "github.com/Financial-Times/api-endpoint"
 	"github.com/Financial-Times/draft-annotations-api/annotations"
+	"github.com/Financial-Times/draft-annotations-api/audit"
 	"github.com/Financial-Times/draft-annotations-api/concept"
+	"github.com/Financial-Times/draft-annotations-api/events"
 	"github.com/Financial-Times/draft-annotations-api/handler"
 	"github.com/Financial-Times/draft-annotations-api/health"
+	"github.com/Financial-Times/draft-annotations-api/history"
+	"github.com/Financial-Times/draft-annotations-api/notifications"
+	"github.com/Financial-Times/draft-annotations-api/webhook"
 	"github.com/Financial-Times/go-ft-http/fthttp"
 	"github.com/Financial-Times/http-handlers-go/httphandlers"
 	status "github.com/Financial-Times/service-status-go/httphandlers"
@@ -22,6 +31,9 @@ import (
 
 const appDescription = "PAC Draft Annotations API"
 
+// shutdownTimeout bounds the time given to the requests in progress and the queued webhook deliveries on shutdown.
+const shutdownTimeout = 20 * time.Second
+
 func main() {
 	app := cli.App("draft-annotations-api", appDescription)
 
@@ -67,6 +79,60 @@ func main() {
 		Desc:   "Concept IDs maximum batch size to use when querying the UPP Internal Concordances API",
 		EnvVar: "INTERNAL_CONCORDANCES_BATCH_SIZE",
 	})
+	internalConcordancesParallelism := app.Int(cli.IntOpt{
+		Name:   "internal-concordances-parallelism",
+		Value:  4,
+		Desc:   "Maximum number of concept ID batches fetched concurrently from the UPP Internal Concordances API",
+		EnvVar: "INTERNAL_CONCORDANCES_PARALLELISM",
+	})
+	internalConcordancesPartialResults := app.Bool(cli.BoolOpt{
+		Name:   "internal-concordances-partial-results",
+		Value:  false,
+		Desc:   "Whether the concept reads return the concepts of the batches fetched successfully when other batches fail, to the concept lookup endpoint and to the degraded reads, instead of failing",
+		EnvVar: "INTERNAL_CONCORDANCES_PARTIAL_RESULTS",
+	})
+	requestCoalescingEnabled := app.Bool(cli.BoolOpt{
+		Name:   "request-coalescing-enabled",
+		Value:  true,
+		Desc:   "Whether concurrent identical reads of the draft annotations, the published annotations and the concepts share one upstream request",
+		EnvVar: "REQUEST_COALESCING_ENABLED",
+	})
+	degradedReadsEnabled := app.Bool(cli.BoolOpt{
+		Name:   "degraded-reads-enabled",
+		Value:  false,
+		Desc:   "Whether the reads of the annotations return them partially augmented instead of failing when the concepts cannot be read",
+		EnvVar: "DEGRADED_READS_ENABLED",
+	})
+	unresolvedConceptsMode := app.String(cli.StringOpt{
+		Name:   "unresolved-concepts-mode",
+		Value:  "lenient",
+		Desc:   "How the writes of annotations whose concepts cannot be found are handled: lenient to save and report them, strict to reject them",
+		EnvVar: "UNRESOLVED_CONCEPTS_MODE",
+	})
+	conceptCacheEnabled := app.Bool(cli.BoolOpt{
+		Name:   "concept-cache-enabled",
+		Value:  false,
+		Desc:   "Whether to cache the concepts read from the UPP Internal Concordances API",
+		EnvVar: "CONCEPT_CACHE_ENABLED",
+	})
+	conceptCacheTTL := app.String(cli.StringOpt{
+		Name:   "concept-cache-ttl",
+		Value:  "10m",
+		Desc:   "Duration a concept is served from the concepts cache before being read again",
+		EnvVar: "CONCEPT_CACHE_TTL",
+	})
+	conceptCacheNegativeTTL := app.String(cli.StringOpt{
+		Name:   "concept-cache-negative-ttl",
+		Value:  "1m",
+		Desc:   "Duration an unknown concept ID is remembered by the concepts cache before being read again",
+		EnvVar: "CONCEPT_CACHE_NEGATIVE_TTL",
+	})
+	conceptCacheMaxSize := app.Int(cli.IntOpt{
+		Name:   "concept-cache-max-size",
+		Value:  10000,
+		Desc:   "Number of concept IDs kept in the concepts cache, after which the least recently used ones are evicted",
+		EnvVar: "CONCEPT_CACHE_MAX_SIZE",
+	})
 	deliveryBasicAuth := app.String(cli.StringOpt{
 		Name:   "delivery-basic-auth",
 		Value:  "username:password",
@@ -85,6 +151,114 @@ func main() {
 		Desc:   "Duration to wait before timing out a request",
 		EnvVar: "HTTP_TIMEOUT",
 	})
+	publishEndpoint := app.String(cli.StringOpt{
+		Name:   "publish-endpoint",
+		Value:  "",
+		Desc:   "Endpoint receiving the published draft annotations, where %s is replaced by the content UUID; publishing is disabled if empty",
+		EnvVar: "PUBLISH_ENDPOINT",
+	})
+	suggestionsEndpoint := app.String(cli.StringOpt{
+		Name:   "upp-suggestions-endpoint",
+		Value:  "",
+		Desc:   "UPP suggestions endpoint, where %v is replaced by the content UUID; the includeSuggestions read mode is disabled if empty",
+		EnvVar: "SUGGESTIONS_ENDPOINT",
+	})
+	conceptSearchEndpoint := app.String(cli.StringOpt{
+		Name:   "concept-search-endpoint",
+		Value:  "",
+		Desc:   "UPP concept search endpoint used by the concept search endpoint, which is disabled if empty",
+		EnvVar: "CONCEPT_SEARCH_ENDPOINT",
+	})
+	historyStore := app.String(cli.StringOpt{
+		Name:   "history-store",
+		Value:  "memory",
+		Desc:   "Where to record the versions of the draft annotations, used as base to merge concurrent changes: none, memory or file",
+		EnvVar: "HISTORY_STORE",
+	})
+	historyDir := app.String(cli.StringOpt{
+		Name:   "history-dir",
+		Value:  "./draft-history",
+		Desc:   "Directory holding the versions of the draft annotations when using the file history store",
+		EnvVar: "HISTORY_DIR",
+	})
+	historyMaxVersions := app.Int(cli.IntOpt{
+		Name:   "history-max-versions",
+		Value:  50,
+		Desc:   "Maximum number of versions of the draft annotations recorded per content, 0 means no limit",
+		EnvVar: "HISTORY_MAX_VERSIONS",
+	})
+	auditSink := app.String(cli.StringOpt{
+		Name:   "audit-sink",
+		Value:  "none",
+		Desc:   "Where to record the audit trail of the changes to the draft annotations: none, log, memory or file",
+		EnvVar: "AUDIT_SINK",
+	})
+	auditDir := app.String(cli.StringOpt{
+		Name:   "audit-dir",
+		Value:  "./draft-audit",
+		Desc:   "Directory holding the audit trail of the draft annotations when using the file audit sink",
+		EnvVar: "AUDIT_DIR",
+	})
+	eventsEnabled := app.Bool(cli.BoolOpt{
+		Name:   "events-enabled",
+		Value:  false,
+		Desc:   "Whether to stream the changes to the draft annotations as Server-Sent Events",
+		EnvVar: "EVENTS_ENABLED",
+	})
+	eventsBufferSize := app.Int(cli.IntOpt{
+		Name:   "events-buffer-size",
+		Value:  16,
+		Desc:   "Number of events buffered for each events stream, further events are dropped for slow clients",
+		EnvVar: "EVENTS_BUFFER_SIZE",
+	})
+	webhookURLs := app.Strings(cli.StringsOpt{
+		Name:   "webhook-urls",
+		Value:  []string{},
+		Desc:   "Comma-separated URLs notified with a POST request of every change to the draft annotations",
+		EnvVar: "WEBHOOK_URLS",
+	})
+	webhookSecret := app.String(cli.StringOpt{
+		Name:   "webhook-secret",
+		Value:  "",
+		Desc:   "Secret used to sign the webhook payloads with HMAC-SHA256",
+		EnvVar: "WEBHOOK_SECRET",
+	})
+	webhookQueueSize := app.Int(cli.IntOpt{
+		Name:   "webhook-queue-size",
+		Value:  1000,
+		Desc:   "Number of changes waiting to be delivered to each webhook, further changes are dropped for it",
+		EnvVar: "WEBHOOK_QUEUE_SIZE",
+	})
+	webhookMaxRetries := app.Int(cli.IntOpt{
+		Name:   "webhook-max-retries",
+		Value:  3,
+		Desc:   "Number of times a failed webhook delivery is retried",
+		EnvVar: "WEBHOOK_MAX_RETRIES",
+	})
+	webhookRetryBackoff := app.String(cli.StringOpt{
+		Name:   "webhook-retry-backoff",
+		Value:  "1s",
+		Desc:   "Delay before the first retry of a failed webhook delivery, doubled for each further retry",
+		EnvVar: "WEBHOOK_RETRY_BACKOFF",
+	})
+	notificationsStore := app.String(cli.StringOpt{
+		Name:   "notifications-store",
+		Value:  "none",
+		Desc:   "Where to record the notifications feed of the changes to the draft annotations: none, memory or file",
+		EnvVar: "NOTIFICATIONS_STORE",
+	})
+	notificationsMaxSize := app.Int(cli.IntOpt{
+		Name:   "notifications-max-size",
+		Value:  10000,
+		Desc:   "Maximum number of notifications kept by the memory notifications store, the oldest ones are dropped beyond it, 0 means no limit",
+		EnvVar: "NOTIFICATIONS_MAX_SIZE",
+	})
+	notificationsFile := app.String(cli.StringOpt{
+		Name:   "notifications-file",
+		Value:  "./draft-notifications.jsonl",
+		Desc:   "File holding the notifications feed of the draft annotations when using the file notifications store",
+		EnvVar: "NOTIFICATIONS_FILE",
+	})
 	logLevel := app.String(cli.StringOpt{
 		Name:   "log-level",
 		Value:  "INFO",
@@ -118,15 +292,128 @@ func main() {
 			log.Fatal("error while resolving basic auth")
 		}
 
-		rw := annotations.NewRW(client, *annotationsRWEndpoint)
-		annotationsAPI := annotations.NewUPPAnnotationsAPI(client, *annotationsAPIEndpoint, basicAuthCredentials[0], basicAuthCredentials[1])
 		c14n := annotations.NewCanonicalizer(annotations.NewCanonicalAnnotationSorter)
-		conceptRead := concept.NewReadAPI(client, *internalConcordancesEndpoint, basicAuthCredentials[0], basicAuthCredentials[1], *internalConcordancesBatchSize)
+		rw := annotations.NewRW(client, *annotationsRWEndpoint)
+		uppAnnotationsAPI := annotations.NewUPPAnnotationsAPI(client, *annotationsAPIEndpoint, basicAuthCredentials[0], basicAuthCredentials[1])
+		var annotationsAPI handler.AnnotationsAPI = uppAnnotationsAPI
+		conceptReadOpts := []concept.ReadOption{concept.WithParallelism(*internalConcordancesParallelism)}
+		if *internalConcordancesPartialResults {
+			conceptReadOpts = append(conceptReadOpts, concept.WithPartialResults())
+		}
+		conceptRead := concept.NewReadAPI(client, *internalConcordancesEndpoint, basicAuthCredentials[0], basicAuthCredentials[1], *internalConcordancesBatchSize, conceptReadOpts...)
+		readRW := rw
+		if *requestCoalescingEnabled {
+			readRW = annotations.NewCoalescingRW(rw, httpTimeout)
+			annotationsAPI = annotations.NewCoalescingAnnotationsAPI(uppAnnotationsAPI, httpTimeout)
+			conceptRead = concept.NewCoalescingReadAPI(conceptRead, httpTimeout)
+		}
+		if *conceptCacheEnabled {
+			ttl, err := time.ParseDuration(*conceptCacheTTL)
+			if err != nil {
+				log.WithError(err).Fatal("Please provide a valid concept cache TTL duration")
+			}
+			negativeTTL, err := time.ParseDuration(*conceptCacheNegativeTTL)
+			if err != nil {
+				log.WithError(err).Fatal("Please provide a valid concept cache negative TTL duration")
+			}
+			conceptRead = concept.NewCachedReadAPI(conceptRead, concept.CacheConfig{
+				TTL:         ttl,
+				NegativeTTL: negativeTTL,
+				MaxSize:     *conceptCacheMaxSize,
+			}, metrics.DefaultRegistry)
+		}
 		augmenter := annotations.NewAugmenter(conceptRead)
-		annotationsHandler := handler.New(rw, annotationsAPI, c14n, augmenter, time.Millisecond*httpTimeout)
-		healthService := health.NewHealthService(*appSystemCode, *appName, appDescription, rw, annotationsAPI, conceptRead)
+		handlerOpts := []handler.Option{handler.WithConceptRead(conceptRead), handler.WithReadRW(readRW)}
+		switch *historyStore {
+		case "none":
+			log.Warn("The history store is disabled, the concurrent changes to the draft annotations will not be merged")
+		case "memory":
+			handlerOpts = append(handlerOpts, handler.WithHistory(history.NewMemoryStore(*historyMaxVersions)))
+		case "file":
+			store, err := history.NewFileStore(*historyDir, *historyMaxVersions)
+			if err != nil {
+				log.WithError(err).Fatal("Unable to create the file history store")
+			}
+			handlerOpts = append(handlerOpts, handler.WithHistory(store))
+		default:
+			log.WithField("historyStore", *historyStore).Fatal("Please provide a valid history store: none, memory or file")
+		}
+		switch *auditSink {
+		case "none":
+		case "log":
+			handlerOpts = append(handlerOpts, handler.WithAudit(audit.NewLogSink()))
+		case "memory":
+			handlerOpts = append(handlerOpts, handler.WithAudit(audit.NewMemorySink()))
+		case "file":
+			sink, err := audit.NewFileSink(*auditDir)
+			if err != nil {
+				log.WithError(err).Fatal("Unable to create the file audit sink")
+			}
+			handlerOpts = append(handlerOpts, handler.WithAudit(sink))
+		default:
+			log.WithField("auditSink", *auditSink).Fatal("Please provide a valid audit sink: none, log, memory or file")
+		}
+		switch *notificationsStore {
+		case "none":
+		case "memory":
+			handlerOpts = append(handlerOpts, handler.WithNotifications(notifications.NewMemoryStore(*notificationsMaxSize)))
+		case "file":
+			store, err := notifications.NewFileStore(*notificationsFile)
+			if err != nil {
+				log.WithError(err).Fatal("Unable to create the file notifications store")
+			}
+			handlerOpts = append(handlerOpts, handler.WithNotifications(store))
+		default:
+			log.WithField("notificationsStore", *notificationsStore).Fatal("Please provide a valid notifications store: none, memory or file")
+		}
+		if *degradedReadsEnabled {
+			handlerOpts = append(handlerOpts, handler.WithDegradedReads())
+		}
+		switch *unresolvedConceptsMode {
+		case "lenient":
+		case "strict":
+			handlerOpts = append(handlerOpts, handler.WithStrictConcepts())
+		default:
+			log.WithField("unresolvedConceptsMode", *unresolvedConceptsMode).Fatal("Please provide a valid unresolved concepts mode: lenient or strict")
+		}
+		if *publishEndpoint != "" {
+			handlerOpts = append(handlerOpts, handler.WithPublisher(annotations.NewPublishAPI(client, *publishEndpoint)))
+		}
+		if *suggestionsEndpoint != "" {
+			suggestionsAPI := annotations.NewUPPSuggestionsAPI(client, *suggestionsEndpoint, basicAuthCredentials[0], basicAuthCredentials[1])
+			handlerOpts = append(handlerOpts, handler.WithSuggestions(suggestionsAPI))
+		}
+		if *conceptSearchEndpoint != "" {
+			conceptSearch := concept.NewSearchAPI(client, *conceptSearchEndpoint, basicAuthCredentials[0], basicAuthCredentials[1])
+			handlerOpts = append(handlerOpts, handler.WithConceptSearch(conceptSearch))
+		}
+		if *eventsEnabled {
+			handlerOpts = append(handlerOpts, handler.WithEvents(events.NewMemoryHub(*eventsBufferSize)))
+		}
+		var closers []func(context.Context) error
+		if len(*webhookURLs) > 0 {
+			if *webhookSecret == "" {
+				log.Fatal("Please provide a webhook secret to sign the webhook payloads")
+			}
+			retryBackoff, err := time.ParseDuration(*webhookRetryBackoff)
+			if err != nil {
+				log.WithError(err).Fatal("Please provide a valid webhook retry backoff duration")
+			}
+			dispatcher := webhook.NewDispatcher(client, webhook.Config{
+				URLs:         *webhookURLs,
+				Secret:       *webhookSecret,
+				QueueSize:    *webhookQueueSize,
+				MaxRetries:   *webhookMaxRetries,
+				RetryBackoff: retryBackoff,
+			}, metrics.DefaultRegistry)
+			handlerOpts = append(handlerOpts, handler.WithWebhooks(dispatcher))
+			closers = append(closers, dispatcher.Close)
+		}
 
-		serveEndpoints(*port, apiYml, annotationsHandler, healthService)
+		annotationsHandler := handler.New(rw, annotationsAPI, c14n, augmenter, time.Millisecond*httpTimeout, handlerOpts...)
+		healthService := health.NewHealthService(*appSystemCode, *appName, appDescription, rw, uppAnnotationsAPI, conceptRead)
+
+		serveEndpoints(*port, apiYml, annotationsHandler, healthService, closers...)
 	}
 
 	err := app.Run(os.Args)
@@ -136,13 +423,29 @@ func main() {
 	}
 }
 
-func serveEndpoints(port string, apiYml *string, handler *handler.Handler, healthService *health.HealthService) {
+// serveEndpoints serves the endpoints until the service receives SIGINT or SIGTERM, then it shuts the server down
+// and calls the given closers, such as the webhook dispatcher delivering the changes already made.
+func serveEndpoints(port string, apiYml *string, handler *handler.Handler, healthService *health.HealthService, closers ...func(context.Context) error) {
 	r := vestigo.NewRouter()
 
+	r.Post("/drafts/content/annotations/batch", handler.BatchReadAnnotations)
+	r.Get("/drafts/notifications", handler.ReadNotifications)
+	r.Get("/drafts/concepts", handler.ReadConcepts)
+	r.Get("/drafts/concepts/search", handler.SearchConcepts)
 	r.Delete("/drafts/content/:uuid/annotations/:cuuid", handler.DeleteAnnotation)
 	r.Get("/drafts/content/:uuid/annotations", handler.ReadAnnotations)
+	r.Get("/drafts/content/:uuid/annotations/diff", handler.DiffAnnotations)
+	r.Get("/drafts/content/:uuid/annotations/preview", handler.PreviewAnnotations)
+	r.Get("/drafts/content/:uuid/annotations/versions", handler.ListVersions)
+	r.Get("/drafts/content/:uuid/annotations/audit", handler.AuditTrail)
+	r.Get("/drafts/content/:uuid/annotations/events", handler.StreamEvents)
+	r.Post("/drafts/content/:uuid/annotations/undo", handler.UndoAnnotations)
+	r.Post("/drafts/content/:uuid/annotations/restore", handler.RestoreAnnotations)
+	r.Post("/drafts/content/:uuid/annotations/publish", handler.PublishAnnotations)
 	r.Put("/drafts/content/:uuid/annotations", handler.WriteAnnotations)
 	r.Post("/drafts/content/:uuid/annotations", handler.AddAnnotation)
+	r.Patch("/drafts/content/:uuid/annotations", handler.PatchAnnotations)
+	r.Delete("/drafts/content/:uuid/annotations", handler.DiscardDraftAnnotations)
 	r.Patch("/drafts/content/:uuid/annotations/:cuuid", handler.ReplaceAnnotation)
 
 	var monitoringRouter http.Handler = r
@@ -164,7 +467,26 @@ func serveEndpoints(port string, apiYml *string, handler *handler.Handler, healt
 		}
 	}
 
-	if err := http.ListenAndServe(":"+port, nil); err != nil {
-		log.Fatalf("Unable to start: %v", err)
+	server := &http.Server{Addr: ":" + port}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Unable to start: %v", err)
+		}
+	}()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	<-stop
+	log.Info("Shutting down...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.WithError(err).Warn("Failed to shut down the server gracefully")
+	}
+	for _, closer := range closers {
+		if err := closer(ctx); err != nil {
+			log.WithError(err).Warn("Failed to complete the pending work before shutting down")
+		}
 	}
 }
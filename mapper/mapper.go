@@ -22,14 +22,44 @@ const (
 	PredicateHasContributor          = "http://www.ft.com/ontology/hasContributor"
 	PredicateHasDisplayTag           = "http://www.ft.com/ontology/hasDisplayTag"
 
+	LifecyclePAC       = "pac"
+	LifecycleV1        = "v1"
+	LifecycleV2        = "v2"
+	LifecycleNextVideo = "next-video"
+
+	ProvenanceHuman   = "human"
+	ProvenanceMachine = "machine"
+
 	ConceptTypeBrand         = "http://www.ft.com/ontology/product/Brand"
 	ConceptTypeGenre         = "http://www.ft.com/ontology/Genre"
 	ConceptTypeTopic         = "http://www.ft.com/ontology/Topic"
 	ConceptTypeLocation      = "http://www.ft.com/ontology/Location"
 	ConceptTypeSpecialReport = "http://www.ft.com/ontology/SpecialReport"
 	ConceptTypeSubject       = "http://www.ft.com/ontology/Subject"
+	ConceptTypePerson        = "http://www.ft.com/ontology/person/Person"
+	ConceptTypeOrganisation  = "http://www.ft.com/ontology/organisation/Organisation"
+	ConceptTypePublicCompany = "http://www.ft.com/ontology/company/PublicCompany"
 )
 
+var aboutConceptTypes = []string{
+	ConceptTypePerson,
+	ConceptTypeOrganisation,
+	ConceptTypePublicCompany,
+	ConceptTypeLocation,
+	ConceptTypeTopic,
+}
+
+// pacConceptTypes holds the concept types that can be annotated with each PAC predicate.
+var pacConceptTypes = map[string][]string{
+	PredicateAbout:          aboutConceptTypes,
+	PredicateMentions:       aboutConceptTypes,
+	PredicateHasDisplayTag:  aboutConceptTypes,
+	PredicateHasAuthor:      {ConceptTypePerson},
+	PredicateHasContributor: {ConceptTypePerson},
+	PredicateIsClassifiedBy: {ConceptTypeBrand, ConceptTypeGenre},
+	PredicateHasBrand:       {ConceptTypeBrand},
+}
+
 func ConvertPredicates(body []byte) ([]byte, error) {
 	originalAnnotations := make([]map[string]interface{}, 0)
 	convertedAnnotations := make([]map[string]interface{}, 0)
@@ -60,6 +90,10 @@ func ConvertPredicates(body []byte) ([]byte, error) {
 		annoMap["type"] = conceptType
 		delete(annoMap, "types")
 
+		if lifecycle, _ := annoMap["lifecycle"].(string); lifecycle != "" {
+			annoMap["provenance"] = Provenance(lifecycle)
+		}
+
 		if conceptType == ConceptTypeSpecialReport || conceptType == ConceptTypeSubject {
 			continue
 		}
@@ -99,6 +133,95 @@ func ConvertPredicates(body []byte) ([]byte, error) {
 	return json.Marshal(convertedAnnotations)
 }
 
+// Provenance returns whether the annotations of the given UPP lifecycle have been curated by a human or generated by
+// a machine. Only v2 annotations are not editorially curated. It returns an empty string for an unknown lifecycle.
+func Provenance(lifecycle string) string {
+	switch lifecycle {
+	case LifecyclePAC, LifecycleV1, LifecycleNextVideo:
+		return ProvenanceHuman
+	case LifecycleV2:
+		return ProvenanceMachine
+	default:
+		return ""
+	}
+}
+
+// PublishedPredicate returns the predicate a PAC annotation with the given predicate and concept type is published with.
+// It reverses the mapping of ConvertPredicates where the published predicate can be recovered: brands are classified
+// with isClassifiedBy in PAC, but published with hasBrand. The other PAC predicates are published as they are.
+func PublishedPredicate(predicate string, conceptType string) string {
+	if predicate == PredicateIsClassifiedBy && conceptType == ConceptTypeBrand {
+		return PredicateHasBrand
+	}
+	return predicate
+}
+
+// ConvertToPublishedPredicates converts a JSON list of PAC annotations, holding their concept type in the type field,
+// to the predicates they are published with. Annotations without a valid PAC predicate are dropped.
+func ConvertToPublishedPredicates(body []byte) ([]byte, error) {
+	pacAnnotations := make([]map[string]interface{}, 0)
+	err := json.Unmarshal(body, &pacAnnotations)
+	if err != nil {
+		return nil, fmt.Errorf("could not unmarshal json body:%w", err)
+	}
+
+	publishedAnnotations := make([]map[string]interface{}, 0)
+	for _, annoMap := range pacAnnotations {
+		predicate, _ := annoMap["predicate"].(string)
+		if !IsValidPACPredicate(predicate) {
+			log.Infof("Invalid PAC predicate not published: %s", predicate)
+			continue
+		}
+		conceptType, _ := annoMap["type"].(string)
+		annoMap["predicate"] = PublishedPredicate(predicate, conceptType)
+
+		publishedAnnotations = append(publishedAnnotations, annoMap)
+	}
+
+	return json.Marshal(publishedAnnotations)
+}
+
+// ConvertImplicitPredicates keeps only the implicit annotations UPP derives from the published ones
+// (implicitlyAbout and implicitlyClassifiedBy), which ConvertPredicates drops, normalising their concept ID and type
+// in the same way. It returns nil if there are no implicit annotations.
+func ConvertImplicitPredicates(body []byte) ([]byte, error) {
+	originalAnnotations := make([]map[string]interface{}, 0)
+	implicitAnnotations := make([]map[string]interface{}, 0)
+	err := json.Unmarshal(body, &originalAnnotations)
+	if err != nil {
+		return nil, fmt.Errorf("could not unmarshal json body:%w", err)
+	}
+
+	for _, annoMap := range originalAnnotations {
+		predicate, _ := annoMap["predicate"].(string)
+		if predicate != PredicateImplicitlyAbout && predicate != PredicateImplicitlyClassifiedBy {
+			continue
+		}
+		someTypes, ok := annoMap["types"]
+		if !ok {
+			log.Info("no types supplied for incoming annotation")
+			continue
+		}
+		stringTypes, err := toStringArray(someTypes)
+		if err != nil || len(stringTypes) == 0 {
+			continue
+		}
+
+		id, _ := annoMap["id"].(string)
+		annoMap["id"] = TransformConceptID(id)
+		annoMap["type"] = getLeafType(stringTypes)
+		delete(annoMap, "types")
+
+		implicitAnnotations = append(implicitAnnotations, annoMap)
+	}
+
+	if len(implicitAnnotations) == 0 {
+		return nil, nil
+	}
+
+	return json.Marshal(implicitAnnotations)
+}
+
 func toStringArray(val interface{}) ([]string, error) {
 	arrVal, ok := val.([]interface{})
 	if !ok {
@@ -139,6 +262,27 @@ func IsValidPACPredicate(pr string) bool {
 	return false
 }
 
+// ConceptTypesForPredicate returns the concept types that can be annotated with the given PAC predicate,
+// or all of the concept types that can be annotated in PAC if the predicate is empty.
+// It returns nil for an invalid PAC predicate.
+func ConceptTypesForPredicate(predicate string) []string {
+	if predicate != "" {
+		return pacConceptTypes[predicate]
+	}
+
+	var types []string
+	seen := make(map[string]struct{})
+	for _, pr := range []string{PredicateAbout, PredicateIsClassifiedBy} {
+		for _, conceptType := range pacConceptTypes[pr] {
+			if _, found := seen[conceptType]; !found {
+				seen[conceptType] = struct{}{}
+				types = append(types, conceptType)
+			}
+		}
+	}
+	return types
+}
+
 func TransformConceptID(id string) string {
 	i := strings.LastIndex(id, "/")
 	if i == -1 || i == len(id)-1 {
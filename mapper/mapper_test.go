@@ -46,3 +46,150 @@ func TestDiscardedAndEmpty(t *testing.T) {
 
 	assert.True(t, actualBody == nil, "some annotations have not been discarded")
 }
+
+func TestConvertToPublishedPredicates(t *testing.T) {
+	tests := []struct {
+		name            string
+		fixtureBaseName string
+	}{
+		{"IsClassifiedByBrandsPublishedWithHasBrand", "annotations_isClassifiedBy"},
+		{"IsPrimarilyClassifiedByBrandsPublishedWithHasBrand", "annotations_isPrimarilyClassifiedBy"},
+		{"AboutPassThrough", "annotations_majorMentions"},
+		{"DefaultPassThrough", "annotations_defaults"},
+		{"ImplicitAnnotationsPassThrough", "annotations_implicit"},
+		{"InvalidAnnotationsPassThrough", "annotations_invalid"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			originalBody, err := os.ReadFile("testdata/" + test.fixtureBaseName + "_PAC.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			expectedBody, err := os.ReadFile("testdata/" + test.fixtureBaseName + "_UPP.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actualBody, err := ConvertToPublishedPredicates(originalBody)
+			assert.NoError(t, err)
+			assert.JSONEq(t, string(expectedBody), string(actualBody), "they do not match")
+		})
+	}
+}
+
+func TestConvertToPublishedPredicatesDropsInvalidPredicates(t *testing.T) {
+	body := []byte(`[
+		{"predicate": "http://www.ft.com/ontology/annotation/majorMentions", "id": "http://www.ft.com/thing/1a2a1a0a-7199-38b8-8a73-e651e2172471"},
+		{"predicate": "http://www.ft.com/ontology/annotation/mentions", "id": "http://www.ft.com/thing/0bc9722e-0a12-31c7-b8b4-ae50187cc557"}
+	]`)
+
+	actualBody, err := ConvertToPublishedPredicates(body)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[{"predicate": "http://www.ft.com/ontology/annotation/mentions", "id": "http://www.ft.com/thing/0bc9722e-0a12-31c7-b8b4-ae50187cc557"}]`, string(actualBody))
+}
+
+func TestPublishedPredicate(t *testing.T) {
+	tests := []struct {
+		predicate   string
+		conceptType string
+		expected    string
+	}{
+		{PredicateIsClassifiedBy, ConceptTypeBrand, PredicateHasBrand},
+		{PredicateIsClassifiedBy, ConceptTypeGenre, PredicateIsClassifiedBy},
+		{PredicateHasBrand, ConceptTypeBrand, PredicateHasBrand},
+		{PredicateAbout, ConceptTypeTopic, PredicateAbout},
+		{PredicateMentions, "", PredicateMentions},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, PublishedPredicate(test.predicate, test.conceptType))
+	}
+}
+
+func TestConvertImplicitPredicates(t *testing.T) {
+	originalBody, err := os.ReadFile("testdata/annotations_implicit_v2.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedBody, err := os.ReadFile("testdata/annotations_implicit_derived.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actualBody, err := ConvertImplicitPredicates(originalBody)
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(expectedBody), string(actualBody), "they do not match")
+}
+
+func TestConvertImplicitPredicatesWithoutImplicitAnnotations(t *testing.T) {
+	originalBody, err := os.ReadFile("testdata/annotations_isClassifiedBy_v2.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actualBody, err := ConvertImplicitPredicates(originalBody)
+	assert.NoError(t, err)
+	assert.Nil(t, actualBody)
+}
+
+func TestConvertPredicatesProvenance(t *testing.T) {
+	tests := []struct {
+		lifecycle          string
+		expectedProvenance string
+	}{
+		{LifecyclePAC, ProvenanceHuman},
+		{LifecycleV1, ProvenanceHuman},
+		{LifecycleNextVideo, ProvenanceHuman},
+		{LifecycleV2, ProvenanceMachine},
+	}
+
+	for _, test := range tests {
+		t.Run(test.lifecycle, func(t *testing.T) {
+			body := []byte(`[{
+				"predicate": "http://www.ft.com/ontology/annotation/mentions",
+				"id": "http://api.ft.com/things/0bc9722e-0a12-31c7-b8b4-ae50187cc557",
+				"types": ["http://www.ft.com/ontology/core/Thing", "http://www.ft.com/ontology/person/Person"],
+				"lifecycle": "` + test.lifecycle + `"
+			}]`)
+
+			actualBody, err := ConvertPredicates(body)
+			assert.NoError(t, err)
+			assert.JSONEq(t, `[{
+				"predicate": "http://www.ft.com/ontology/annotation/mentions",
+				"id": "http://www.ft.com/thing/0bc9722e-0a12-31c7-b8b4-ae50187cc557",
+				"type": "http://www.ft.com/ontology/person/Person",
+				"lifecycle": "`+test.lifecycle+`",
+				"provenance": "`+test.expectedProvenance+`"
+			}]`, string(actualBody))
+		})
+	}
+}
+
+func TestProvenance(t *testing.T) {
+	assert.Equal(t, ProvenanceHuman, Provenance(LifecyclePAC))
+	assert.Equal(t, ProvenanceMachine, Provenance(LifecycleV2))
+	assert.Empty(t, Provenance(""))
+	assert.Empty(t, Provenance("unknown"))
+}
+
+func TestConceptTypesForPredicate(t *testing.T) {
+	tests := []struct {
+		name      string
+		predicate string
+		expected  []string
+	}{
+		{"About", PredicateAbout, []string{ConceptTypePerson, ConceptTypeOrganisation, ConceptTypePublicCompany, ConceptTypeLocation, ConceptTypeTopic}},
+		{"HasAuthor", PredicateHasAuthor, []string{ConceptTypePerson}},
+		{"IsClassifiedBy", PredicateIsClassifiedBy, []string{ConceptTypeBrand, ConceptTypeGenre}},
+		{"HasBrand", PredicateHasBrand, []string{ConceptTypeBrand}},
+		{"AnyPredicate", "", []string{ConceptTypePerson, ConceptTypeOrganisation, ConceptTypePublicCompany, ConceptTypeLocation, ConceptTypeTopic, ConceptTypeBrand, ConceptTypeGenre}},
+		{"InvalidPredicate", PredicateMajorMentions, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, ConceptTypesForPredicate(test.predicate))
+		})
+	}
+}
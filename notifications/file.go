@@ -0,0 +1,65 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Financial-Times/draft-annotations-api/internal/jsonl"
+)
+
+type fileStore struct {
+	sync.Mutex
+	path string
+}
+
+// NewFileStore returns a Store appending the notifications to the given JSON lines file,
+// whose directory is created if it does not exist. The whole file is scanned to serve each request,
+// so it is meant to be used locally.
+func NewFileStore(path string) (Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("creating notifications directory: %w", err)
+	}
+	return &fileStore{path: path}, nil
+}
+
+func (s *fileStore) Append(_ context.Context, notification Notification) error {
+	s.Lock()
+	defer s.Unlock()
+
+	return jsonl.Append(s.path, notification)
+}
+
+func (s *fileStore) Since(_ context.Context, since time.Time, limit int) ([]Notification, Cursor, error) {
+	notifications, err := s.readAll()
+	if err != nil {
+		return nil, 0, err
+	}
+	result, next := page(notifications, 0, since, limit)
+	return result, next, nil
+}
+
+func (s *fileStore) After(_ context.Context, cursor Cursor, limit int) ([]Notification, Cursor, error) {
+	notifications, err := s.readAll()
+	if err != nil {
+		return nil, 0, err
+	}
+	result, next := page(notifications, cursor, time.Time{}, limit)
+	return result, next, nil
+}
+
+// readAll returns all the notifications of the file in the order they have been appended,
+// which is the order of the lines, so that the cursors are the line numbers.
+func (s *fileStore) readAll() ([]Notification, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	notifications, err := jsonl.Read[Notification](s.path)
+	if err != nil {
+		return nil, fmt.Errorf("reading notifications: %w", err)
+	}
+	return notifications, nil
+}
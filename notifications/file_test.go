@@ -0,0 +1,44 @@
+package notifications
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileStore(t *testing.T) {
+	s, err := NewFileStore(filepath.Join(t.TempDir(), "notifications", "notifications.jsonl"))
+	assert.NoError(t, err)
+	testStore(t, s)
+}
+
+func TestFileStorePersistsNotifications(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifications.jsonl")
+	ctx := context.Background()
+
+	s, err := NewFileStore(path)
+	assert.NoError(t, err)
+	err = s.Append(ctx, testNotifications()[0])
+	assert.NoError(t, err)
+
+	reopened, err := NewFileStore(path)
+	assert.NoError(t, err)
+	notifications, _, err := reopened.Since(ctx, time.Time{}, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, testNotifications()[:1], notifications)
+}
+
+func TestFileStoreCorruptedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifications.jsonl")
+	err := os.WriteFile(path, []byte("not json\n"), 0o644)
+	assert.NoError(t, err)
+
+	s, err := NewFileStore(path)
+	assert.NoError(t, err)
+	_, _, err = s.Since(context.Background(), time.Time{}, 10)
+	assert.Error(t, err)
+}
@@ -0,0 +1,54 @@
+package notifications
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryStore struct {
+	sync.RWMutex
+	notifications    []Notification
+	maxNotifications int
+	// dropped is the number of the oldest notifications which have been dropped, the cursor of the first one kept
+	dropped Cursor
+}
+
+// NewMemoryStore returns a Store keeping up to maxNotifications notifications in memory, which are lost when
+// the service restarts. The oldest notifications are dropped beyond that number, a maxNotifications value of zero
+// means no limit. A cursor pointing before the notifications kept is followed from the oldest one kept.
+func NewMemoryStore(maxNotifications int) Store {
+	return &memoryStore{maxNotifications: maxNotifications}
+}
+
+func (s *memoryStore) Append(_ context.Context, notification Notification) error {
+	s.Lock()
+	defer s.Unlock()
+
+	s.notifications = append(s.notifications, notification)
+	if s.maxNotifications > 0 && len(s.notifications) > s.maxNotifications {
+		excess := len(s.notifications) - s.maxNotifications
+		s.notifications = s.notifications[excess:]
+		s.dropped += Cursor(excess)
+	}
+	return nil
+}
+
+func (s *memoryStore) Since(_ context.Context, since time.Time, limit int) ([]Notification, Cursor, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	result, next := page(s.notifications, 0, since, limit)
+	return result, s.dropped + next, nil
+}
+
+func (s *memoryStore) After(_ context.Context, cursor Cursor, limit int) ([]Notification, Cursor, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	if cursor < s.dropped {
+		cursor = s.dropped
+	}
+	result, next := page(s.notifications, cursor-s.dropped, time.Time{}, limit)
+	return result, s.dropped + next, nil
+}
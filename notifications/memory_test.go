@@ -0,0 +1,41 @@
+package notifications
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore(0))
+}
+
+func TestMemoryStoreMaxNotifications(t *testing.T) {
+	ctx := context.Background()
+	n := testNotifications()
+	s := NewMemoryStore(2)
+
+	for _, notification := range n {
+		err := s.Append(ctx, notification)
+		assert.NoError(t, err)
+	}
+
+	// the oldest notification has been dropped, the cursors still count it
+	notifications, cursor, err := s.Since(ctx, time.Time{}, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, n[1:], notifications)
+	assert.Equal(t, Cursor(3), cursor)
+
+	notifications, cursor, err = s.After(ctx, 2, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, n[2:], notifications)
+	assert.Equal(t, Cursor(3), cursor)
+
+	// a cursor pointing to a dropped notification is followed from the oldest one kept
+	notifications, cursor, err = s.After(ctx, 0, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, n[1:2], notifications)
+	assert.Equal(t, Cursor(2), cursor)
+}
@@ -0,0 +1,46 @@
+package notifications
+
+import (
+	"context"
+	"time"
+)
+
+// Notification records a change to the draft annotations of a content.
+// Type is the type of the change, as the events type, and Hash the Document-Hash of the draft after the change,
+// not set when the draft has been discarded. PublishReference is the transaction ID of the change.
+type Notification struct {
+	Type             string    `json:"type"`
+	ContentUUID      string    `json:"uuid"`
+	Hash             string    `json:"hash,omitempty"`
+	PublishReference string    `json:"publishReference"`
+	LastModified     time.Time `json:"lastModified"`
+}
+
+// Cursor is the position of the feed in a Store: the number of notifications appended before it.
+// The notifications appended later are after it, whatever their time, so that none is missed by a reader
+// following the cursors, even if concurrent changes are appended out of order or at the same time.
+type Cursor uint64
+
+// Store records the notifications of the changes to the draft annotations of all contents.
+type Store interface {
+	// Append records the given notification.
+	Append(ctx context.Context, notification Notification) error
+	// Since returns up to limit notifications modified after the given time, in the order they have been appended,
+	// and the cursor of the feed after them.
+	Since(ctx context.Context, since time.Time, limit int) ([]Notification, Cursor, error)
+	// After returns up to limit notifications appended after the given cursor, in the order they have been appended,
+	// and the cursor of the feed after them.
+	After(ctx context.Context, cursor Cursor, limit int) ([]Notification, Cursor, error)
+}
+
+// page returns up to limit of the given notifications, in the order they have been appended, from the given cursor
+// and modified after the given time, and the cursor after them.
+func page(notifications []Notification, cursor Cursor, since time.Time, limit int) ([]Notification, Cursor) {
+	result := make([]Notification, 0)
+	for ; cursor < Cursor(len(notifications)) && len(result) < limit; cursor++ {
+		if notifications[cursor].LastModified.After(since) {
+			result = append(result, notifications[cursor])
+		}
+	}
+	return result, cursor
+}
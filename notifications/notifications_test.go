@@ -0,0 +1,92 @@
+package notifications
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testTime = time.Date(2023, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+func testNotifications() []Notification {
+	return []Notification{
+		{Type: "saved", ContentUUID: "83a201c6-60cd-11e7-91a7-502f7ee26895", Hash: "hash-1", PublishReference: "tid_1", LastModified: testTime},
+		{Type: "saved", ContentUUID: "9577c6d4-b09e-4552-b88f-e52745abe02b", Hash: "hash-2", PublishReference: "tid_2", LastModified: testTime.Add(time.Minute)},
+		{Type: "discarded", ContentUUID: "83a201c6-60cd-11e7-91a7-502f7ee26895", PublishReference: "tid_3", LastModified: testTime.Add(2 * time.Minute)},
+	}
+}
+
+// testStore checks the behaviour shared by all Store implementations.
+func testStore(t *testing.T, s Store) {
+	ctx := context.Background()
+	n := testNotifications()
+
+	notifications, cursor, err := s.Since(ctx, time.Time{}, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, notifications)
+	assert.Equal(t, Cursor(0), cursor)
+
+	// the last notification is appended first, as by a concurrent change
+	for _, i := range []int{2, 0, 1} {
+		err = s.Append(ctx, n[i])
+		assert.NoError(t, err)
+	}
+
+	notifications, cursor, err = s.Since(ctx, time.Time{}, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []Notification{n[2], n[0], n[1]}, notifications)
+	assert.Equal(t, Cursor(3), cursor)
+
+	notifications, cursor, err = s.Since(ctx, testTime, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []Notification{n[2], n[1]}, notifications)
+	assert.Equal(t, Cursor(3), cursor)
+
+	notifications, cursor, err = s.Since(ctx, testTime.Add(-time.Second), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []Notification{n[2], n[0]}, notifications)
+	assert.Equal(t, Cursor(2), cursor)
+
+	notifications, cursor, err = s.Since(ctx, testTime.Add(time.Hour), 10)
+	assert.NoError(t, err)
+	assert.Empty(t, notifications)
+	assert.Equal(t, Cursor(3), cursor)
+
+	notifications, cursor, err = s.After(ctx, 2, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []Notification{n[1]}, notifications)
+	assert.Equal(t, Cursor(3), cursor)
+
+	// a notification appended after the cursor is returned even if it is older than the ones before it
+	late := Notification{Type: "saved", ContentUUID: n[0].ContentUUID, Hash: "hash-4", PublishReference: "tid_4", LastModified: testTime}
+	err = s.Append(ctx, late)
+	assert.NoError(t, err)
+
+	notifications, cursor, err = s.After(ctx, cursor, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []Notification{late}, notifications)
+	assert.Equal(t, Cursor(4), cursor)
+
+	notifications, cursor, err = s.After(ctx, cursor, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, notifications)
+	assert.Equal(t, Cursor(4), cursor)
+}
+
+func TestPageSameTime(t *testing.T) {
+	same := []Notification{
+		{Type: "saved", ContentUUID: "83a201c6-60cd-11e7-91a7-502f7ee26895", Hash: "hash-1", LastModified: testTime},
+		{Type: "saved", ContentUUID: "9577c6d4-b09e-4552-b88f-e52745abe02b", Hash: "hash-2", LastModified: testTime},
+		{Type: "saved", ContentUUID: "0a619d71-9af5-3755-90dd-f789b686c67a", Hash: "hash-3", LastModified: testTime},
+	}
+
+	first, cursor := page(same, 0, testTime.Add(-time.Second), 2)
+	assert.Equal(t, same[:2], first)
+
+	// the notification with the same time as the last one of the previous page is not missed
+	next, cursor := page(same, cursor, time.Time{}, 2)
+	assert.Equal(t, same[2:], next)
+	assert.Equal(t, Cursor(3), cursor)
+}
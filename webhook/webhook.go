@@ -0,0 +1,234 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Financial-Times/draft-annotations-api/events"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	metrics "github.com/rcrowley/go-metrics"
+	log "github.com/sirupsen/logrus"
+)
+
+// SignatureHeader holds the HMAC-SHA256 signature of the timestamp and the payload,
+// as "sha256=" followed by its hex encoding, see Sign.
+const SignatureHeader = "X-Draft-Annotations-Signature"
+
+// TimestampHeader holds the time a delivery has been sent at, in seconds since the Unix epoch.
+// It is signed with the payload, so that receivers can reject the replays of old deliveries.
+const TimestampHeader = "X-Draft-Annotations-Timestamp"
+
+// ErrQueueFull is returned when an event is dropped because the delivery queue of a webhook is full.
+var ErrQueueFull = errors.New("webhook delivery queue is full")
+
+// Config configures the webhook deliveries.
+type Config struct {
+	// URLs are the endpoints receiving every event.
+	URLs []string
+	// Secret is the key used to sign the payloads.
+	Secret string
+	// QueueSize is the number of events waiting to be delivered to each webhook, after which further events are dropped.
+	QueueSize int
+	// MaxRetries is the number of times a failed delivery is retried.
+	MaxRetries int
+	// RetryBackoff is the delay before the first retry, doubled for each further retry.
+	RetryBackoff time.Duration
+}
+
+// Dispatcher POSTs the events of the changes to the draft annotations to the configured webhooks.
+// Each webhook has its own queue and background worker delivering its events one at a time, in order,
+// so that a slow or failing webhook does not delay the deliveries to the others.
+type Dispatcher struct {
+	client    *http.Client
+	config    Config
+	endpoints []*endpoint
+	workers   sync.WaitGroup
+	stop      chan struct{}
+
+	closeOnce sync.Once
+	stopOnce  sync.Once
+	closeLock sync.RWMutex
+	closed    bool
+
+	delivered metrics.Counter
+	failed    metrics.Counter
+	retried   metrics.Counter
+	dropped   metrics.Counter
+	latency   metrics.Timer
+}
+
+// endpoint is a webhook with the queue of the events waiting to be delivered to it.
+type endpoint struct {
+	url   string
+	queue chan delivery
+}
+
+// delivery is an event with its encoded payload.
+type delivery struct {
+	event events.Event
+	body  []byte
+}
+
+// NewDispatcher starts a Dispatcher delivering the events to the configured webhooks,
+// and registers the delivery metrics in the given registry.
+func NewDispatcher(client *http.Client, config Config, registry metrics.Registry) *Dispatcher {
+	d := &Dispatcher{
+		client:    client,
+		config:    config,
+		stop:      make(chan struct{}),
+		delivered: metrics.GetOrRegisterCounter("webhook.delivered", registry),
+		failed:    metrics.GetOrRegisterCounter("webhook.failed", registry),
+		retried:   metrics.GetOrRegisterCounter("webhook.retried", registry),
+		dropped:   metrics.GetOrRegisterCounter("webhook.dropped", registry),
+		latency:   metrics.GetOrRegisterTimer("webhook.delivery", registry),
+	}
+	for _, url := range config.URLs {
+		e := &endpoint{url: url, queue: make(chan delivery, config.QueueSize)}
+		d.endpoints = append(d.endpoints, e)
+		d.workers.Add(1)
+		go d.run(e)
+	}
+	return d
+}
+
+// Publish queues the given event for delivery to every webhook.
+// It returns ErrQueueFull if the queue of any webhook is full, in which case the event is not delivered to it.
+func (d *Dispatcher) Publish(_ context.Context, event events.Event) error {
+	d.closeLock.RLock()
+	defer d.closeLock.RUnlock()
+
+	if d.closed {
+		return errors.New("webhook dispatcher is closed")
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encoding webhook payload: %w", err)
+	}
+
+	var result error
+	for _, e := range d.endpoints {
+		select {
+		case e.queue <- delivery{event: event, body: body}:
+		default:
+			d.dropped.Inc(1)
+			result = ErrQueueFull
+		}
+	}
+	return result
+}
+
+// Close stops accepting events and waits for the queued ones to be delivered.
+// If the given context is done first, the pending retries are abandoned and the context error is returned.
+func (d *Dispatcher) Close(ctx context.Context) error {
+	d.closeOnce.Do(func() {
+		d.closeLock.Lock()
+		d.closed = true
+		for _, e := range d.endpoints {
+			close(e.queue)
+		}
+		d.closeLock.Unlock()
+	})
+
+	done := make(chan struct{})
+	go func() {
+		d.workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		d.stopOnce.Do(func() { close(d.stop) })
+		return ctx.Err()
+	}
+}
+
+func (d *Dispatcher) run(e *endpoint) {
+	defer d.workers.Done()
+	for delivery := range e.queue {
+		d.deliver(e.url, delivery)
+	}
+}
+
+// deliver POSTs the given payload to a webhook, retrying with an exponential backoff on failure
+// until the dispatcher is stopped.
+func (d *Dispatcher) deliver(url string, delivery delivery) {
+	deliveryLog := log.WithField(tidutils.TransactionIDKey, delivery.event.TransactionID).
+		WithField("uuid", delivery.event.ContentUUID).
+		WithField("webhook", url)
+
+	backoff := d.config.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		start := time.Now()
+		retry, err := d.post(url, delivery)
+		d.latency.UpdateSince(start)
+		if err == nil {
+			d.delivered.Inc(1)
+			deliveryLog.Debug("Delivered draft annotations webhook")
+			return
+		}
+		if !retry || attempt >= d.config.MaxRetries {
+			d.failed.Inc(1)
+			deliveryLog.WithError(err).Error("Failed to deliver draft annotations webhook")
+			return
+		}
+
+		d.retried.Inc(1)
+		deliveryLog.WithError(err).Warnf("Failed to deliver draft annotations webhook, retrying in %v", backoff)
+		select {
+		case <-time.After(backoff):
+		case <-d.stop:
+			d.failed.Inc(1)
+			deliveryLog.WithError(err).Error("Failed to deliver draft annotations webhook before the dispatcher has been stopped")
+			return
+		}
+		backoff *= 2
+	}
+}
+
+// post sends the payload once, returning whether a failure is worth retrying.
+func (d *Dispatcher) post(url string, delivery delivery) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(delivery.body))
+	if err != nil {
+		return false, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(d.config.Secret, timestamp, delivery.body))
+	if delivery.event.TransactionID != "" {
+		req.Header.Set(tidutils.TransactionIDHeader, delivery.event.TransactionID)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	return retry, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+}
+
+// Sign returns the value of the SignatureHeader for the given value of the TimestampHeader and payload,
+// the HMAC of the timestamp, a dot and the payload.
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
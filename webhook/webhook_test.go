@@ -0,0 +1,215 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Financial-Times/draft-annotations-api/events"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	metrics "github.com/rcrowley/go-metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSecret = "top-secret"
+
+var testEvent = events.Event{
+	Type:          events.Saved,
+	ContentUUID:   "83a201c6-60cd-11e7-91a7-502f7ee26895",
+	Hash:          "new-hash",
+	PreviousHash:  "old-hash",
+	TransactionID: "tid_test",
+}
+
+func testConfig(urls ...string) Config {
+	return Config{
+		URLs:         urls,
+		Secret:       testSecret,
+		QueueSize:    4,
+		MaxRetries:   2,
+		RetryBackoff: time.Millisecond,
+	}
+}
+
+func TestDeliverSignedPayload(t *testing.T) {
+	received := make(chan events.Event, 1)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		timestamp, err := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+		assert.NoError(t, err)
+		assert.WithinDuration(t, time.Now(), time.Unix(timestamp, 0), time.Minute)
+		assert.Equal(t, Sign(testSecret, r.Header.Get(TimestampHeader), body), r.Header.Get(SignatureHeader))
+		assert.Equal(t, "tid_test", r.Header.Get(tidutils.TransactionIDHeader))
+
+		var event events.Event
+		assert.NoError(t, json.Unmarshal(body, &event))
+		received <- event
+	}))
+	defer s.Close()
+
+	registry := metrics.NewRegistry()
+	d := NewDispatcher(http.DefaultClient, testConfig(s.URL), registry)
+	err := d.Publish(context.Background(), testEvent)
+	assert.NoError(t, err)
+	assert.NoError(t, d.Close(context.Background()))
+
+	assert.Equal(t, testEvent, <-received)
+	assert.Equal(t, int64(1), registry.Get("webhook.delivered").(metrics.Counter).Count())
+	assert.Equal(t, int64(1), registry.Get("webhook.delivery").(metrics.Timer).Count())
+}
+
+func TestDeliverRetries(t *testing.T) {
+	var calls int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer s.Close()
+
+	registry := metrics.NewRegistry()
+	d := NewDispatcher(http.DefaultClient, testConfig(s.URL), registry)
+	err := d.Publish(context.Background(), testEvent)
+	assert.NoError(t, err)
+	assert.NoError(t, d.Close(context.Background()))
+
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	assert.Equal(t, int64(2), registry.Get("webhook.retried").(metrics.Counter).Count())
+	assert.Equal(t, int64(1), registry.Get("webhook.delivered").(metrics.Counter).Count())
+}
+
+func TestDeliverFailures(t *testing.T) {
+	tests := map[string]struct {
+		status        int
+		expectedCalls int32
+	}{
+		"retries exhausted": {
+			status:        http.StatusInternalServerError,
+			expectedCalls: 3,
+		},
+		"client error": {
+			status:        http.StatusBadRequest,
+			expectedCalls: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var calls int32
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				w.WriteHeader(test.status)
+			}))
+			defer s.Close()
+
+			registry := metrics.NewRegistry()
+			d := NewDispatcher(http.DefaultClient, testConfig(s.URL), registry)
+			err := d.Publish(context.Background(), testEvent)
+			assert.NoError(t, err)
+			assert.NoError(t, d.Close(context.Background()))
+
+			assert.Equal(t, test.expectedCalls, atomic.LoadInt32(&calls))
+			assert.Equal(t, int64(1), registry.Get("webhook.failed").(metrics.Counter).Count())
+			assert.Equal(t, int64(0), registry.Get("webhook.delivered").(metrics.Counter).Count())
+		})
+	}
+}
+
+func TestPublishQueueFull(t *testing.T) {
+	release := make(chan struct{})
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer s.Close()
+
+	registry := metrics.NewRegistry()
+	config := testConfig(s.URL)
+	config.QueueSize = 1
+	d := NewDispatcher(http.DefaultClient, config, registry)
+
+	// the first event is taken by the worker, which is blocked by the webhook, and the second one fills the queue
+	var err error
+	for i := 0; i < 10 && err == nil; i++ {
+		err = d.Publish(context.Background(), testEvent)
+	}
+	assert.True(t, errors.Is(err, ErrQueueFull))
+	assert.Equal(t, int64(1), registry.Get("webhook.dropped").(metrics.Counter).Count())
+
+	close(release)
+	assert.NoError(t, d.Close(context.Background()))
+
+	err = d.Publish(context.Background(), testEvent)
+	assert.Error(t, err)
+}
+
+func TestSlowWebhookDoesNotDelayOthers(t *testing.T) {
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer slow.Close()
+	received := make(chan struct{}, 2)
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+	}))
+	defer fast.Close()
+
+	registry := metrics.NewRegistry()
+	d := NewDispatcher(http.DefaultClient, testConfig(slow.URL, fast.URL), registry)
+	for i := 0; i < 2; i++ {
+		err := d.Publish(context.Background(), testEvent)
+		assert.NoError(t, err)
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-received:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the events have not been delivered to the fast webhook")
+		}
+	}
+
+	close(release)
+	assert.NoError(t, d.Close(context.Background()))
+	assert.Equal(t, int64(4), registry.Get("webhook.delivered").(metrics.Counter).Count())
+}
+
+func TestCloseAbandonsRetries(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer s.Close()
+
+	registry := metrics.NewRegistry()
+	config := testConfig(s.URL)
+	config.RetryBackoff = time.Hour
+	d := NewDispatcher(http.DefaultClient, config, registry)
+	err := d.Publish(context.Background(), testEvent)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = d.Close(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	assert.Eventually(t, func() bool {
+		return registry.Get("webhook.failed").(metrics.Counter).Count() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestSign(t *testing.T) {
+	assert.Equal(t, "sha256=eeb7c479d18a52649a9d90ddac6d78c039ecc5fdfab9ec34bda48eed7af8144e", Sign("key", "1677664800", []byte("payload")))
+	assert.NotEqual(t, Sign("key", "1677664800", []byte("payload")), Sign("another-key", "1677664800", []byte("payload")))
+	// a payload replayed later does not match the signature of the new timestamp
+	assert.NotEqual(t, Sign("key", "1677664800", []byte("payload")), Sign("key", "1677668400", []byte("payload")))
+}